@@ -0,0 +1,187 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	actionsLock sync.Mutex
+	// actions contains the actions registered with OnAction whose context is
+	// not done, in registration order.
+	actions []*Action
+)
+
+// Action is an action registered with [OnAction].
+type Action struct {
+	// Name is the name of the action.
+	Name string
+	// Signal is the signal that runs the action.
+	Signal os.Signal
+}
+
+// ActionOption is an option for [OnAction].
+type ActionOption func(*actionOptions)
+
+// WithActionLogger returns a new ActionOption that logs runs of the action,
+// and the errors of failed runs, to the given [*slog.Logger].
+//
+// The default is to log to [slog.Default].
+func WithActionLogger(logger *slog.Logger) ActionOption {
+	return func(actionOptions *actionOptions) {
+		actionOptions.logger = logger
+	}
+}
+
+type actionOptions struct {
+	logger *slog.Logger
+}
+
+func newActionOptions(opts []ActionOption) *actionOptions {
+	actionOptions := &actionOptions{
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(actionOptions)
+	}
+	return actionOptions
+}
+
+// OnAction registers the action with the given name to be run by calling fn,
+// with ctx as the context, whenever sig arrives, until ctx is done.
+//
+// This is intended for operational one-offs triggered by the [UserSignals],
+// syscall.SIGUSR1 and syscall.SIGUSR2, alongside interrupt handling:
+//
+//	interrupt.OnAction(ctx, syscall.SIGUSR1, "rotate credentials", func(ctx context.Context) error {
+//	  return credentials.Rotate(ctx)
+//	})
+//
+// While ctx is not done, sig no longer terminates the program. Runs of the
+// action are made sequentially from a single goroutine, and the errors of
+// failed runs are logged. Actions registered for the same signal run
+// concurrently with each other. Note that an [Upgrader] also uses
+// syscall.SIGUSR2, as one of the [UpgradeSignals]. The actions that are
+// registered are returned by [ReadActions].
+func OnAction(ctx context.Context, sig os.Signal, name string, fn func(context.Context) error, opts ...ActionOption) {
+	actionOptions := newActionOptions(opts)
+	action := &Action{
+		Name:   name,
+		Signal: sig,
+	}
+	actionsLock.Lock()
+	actions = append(actions, action)
+	actionsLock.Unlock()
+	context.AfterFunc(ctx, func() {
+		actionsLock.Lock()
+		defer actionsLock.Unlock()
+		actions = slices.DeleteFunc(actions, func(other *Action) bool {
+			return other == action
+		})
+	})
+	onSignal(ctx, func(sig os.Signal) {
+		attrs := []slog.Attr{
+			slog.String("action", name),
+			slog.String("signal", sig.String()),
+		}
+		actionOptions.logger.LogAttrs(ctx, slog.LevelInfo, "running action", attrs...)
+		if err := fn(ctx); err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+			actionOptions.logger.LogAttrs(ctx, slog.LevelError, "action failed", attrs...)
+		}
+	}, []os.Signal{sig})
+}
+
+// onTimedAction registers an action with OnAction that calls start when sig
+// arrives, and calls the stop function returned by start once duration elapses,
+// if positive, or once ctx is done. If toggle is true, the stop function is
+// also called when sig arrives again. Otherwise, signals that arrive before the
+// stop function is called are ignored.
+//
+// The returned function calls the stop function, if it has not been called.
+func onTimedAction(ctx context.Context, sig os.Signal, name string, duration time.Duration, toggle bool, start func(context.Context) (stop func(context.Context) error, err error), opts []ActionOption) (stop func(context.Context) error) {
+	var lock sync.Mutex
+	// stopStarted is non-nil while what start started is in progress.
+	var stopStarted func(context.Context) error
+	var timer *time.Timer
+	stopLocked := func(ctx context.Context) error {
+		if stopStarted == nil {
+			return nil
+		}
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		err := stopStarted(ctx)
+		stopStarted = nil
+		return err
+	}
+	stop = func(ctx context.Context) error {
+		lock.Lock()
+		defer lock.Unlock()
+		return stopLocked(ctx)
+	}
+	context.AfterFunc(ctx, func() {
+		_ = stop(context.Background())
+	})
+	OnAction(ctx, sig, name, func(ctx context.Context) error {
+		lock.Lock()
+		defer lock.Unlock()
+		if stopStarted != nil {
+			if toggle {
+				return stopLocked(ctx)
+			}
+			return nil
+		}
+		started, err := start(ctx)
+		if err != nil {
+			return err
+		}
+		stopStarted = started
+		if duration > 0 {
+			var expired *time.Timer
+			expired = time.AfterFunc(duration, func() {
+				lock.Lock()
+				defer lock.Unlock()
+				// What was started may have been stopped, and started again,
+				// since.
+				if timer == expired {
+					_ = stopLocked(ctx)
+				}
+			})
+			timer = expired
+		}
+		return nil
+	}, opts...)
+	return stop
+}
+
+// ReadActions returns the actions registered with [OnAction] whose context is
+// not done, in registration order.
+func ReadActions() []Action {
+	actionsLock.Lock()
+	defer actionsLock.Unlock()
+	registered := make([]Action, len(actions))
+	for i, action := range actions {
+		registered[i] = *action
+	}
+	return registered
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	participantsLock sync.Mutex
+	// participants contains the participants that have not acknowledged yet,
+	// in registration order.
+	participants []*participant
+)
+
+type participant struct {
+	name string
+	ackC chan struct{}
+	ack  func()
+}
+
+// Participate registers a shutdown participant with the given name, and returns
+// a function to call once the participant has completed its shutdown.
+//
+// [Shutdown], and therefore [Main], does not return until every participant has
+// acknowledged, so that the program cannot exit while a participant is still in
+// the middle of a flush:
+//
+//	ack := interrupt.Participate("uploader")
+//	go func() {
+//	  defer ack()
+//	  <-ctx.Done()
+//	  uploader.Flush()
+//	}()
+//
+// The wait is bounded by the [context.Context] passed to Shutdown and by
+// [WithAckTimeout], and otherwise by [WithGracePeriod]. The returned function
+// may be called more than once.
+func Participate(name string) (ack func()) {
+	registered := &participant{
+		name: name,
+		ackC: make(chan struct{}),
+	}
+	registered.ack = sync.OnceFunc(func() {
+		participantsLock.Lock()
+		participants = slices.DeleteFunc(participants, func(other *participant) bool {
+			return other == registered
+		})
+		participantsLock.Unlock()
+		close(registered.ackC)
+	})
+	participantsLock.Lock()
+	participants = append(participants, registered)
+	participantsLock.Unlock()
+	return registered.ack
+}
+
+// WithAckTimeout returns a new ShutdownOption that bounds the time that
+// [Shutdown] waits for the participants registered with [Participate] to
+// acknowledge, after the hooks have been called, and then for the children
+// started with [StartChild] to exit.
+//
+// The default is to wait until the [context.Context] passed to Shutdown is
+// done. A zero or negative timeout is ignored.
+func WithAckTimeout(timeout time.Duration) ShutdownOption {
+	return func(shutdownOptions *shutdownOptions) {
+		shutdownOptions.ackTimeout = timeout
+	}
+}
+
+// waitParticipants waits until all participants registered with Participate
+// have acknowledged, and returns the names of the participants that have not
+// acknowledged if ctx is done or timeout elapses first.
+func waitParticipants(ctx context.Context, timeout time.Duration) []string {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	participantsLock.Lock()
+	pending := slices.Clone(participants)
+	participantsLock.Unlock()
+	for i, participant := range pending {
+		select {
+		case <-participant.ackC:
+		case <-ctx.Done():
+			var unacknowledged []string
+			for _, participant := range pending[i:] {
+				select {
+				case <-participant.ackC:
+				default:
+					unacknowledged = append(unacknowledged, participant.name)
+				}
+			}
+			return unacknowledged
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParticipate(t *testing.T) {
+	resetGlobals(t)
+	ack := Participate("worker")
+	defer ack()
+	Participate("acknowledged")()
+	err := Shutdown(context.Background(), WithAckTimeout(10*time.Millisecond))
+	if err == nil {
+		t.Fatal("Shutdown() = nil with an unacknowledged participant")
+	}
+	report, _ := ReadShutdownReport()
+	if len(report.Unacknowledged) != 1 || report.Unacknowledged[0] != "worker" {
+		t.Errorf("Unacknowledged = %v, want [worker]", report.Unacknowledged)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	childrenLock sync.Mutex
+	// children contains the children started with StartChild that have not
+	// exited yet, in start order.
+	children []*Child
+)
+
+// Child is a child process started with [StartChild].
+type Child struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// StartChild starts cmd, and tracks it until it exits.
+//
+// [Shutdown], and therefore [Main], does not return until every child started
+// with StartChild has exited, so that the program does not exit while a child
+// is still stopping, leaving it orphaned:
+//
+//	child, err := interrupt.StartChild(interrupt.Command(ctx, "worker"))
+//	if err != nil {
+//	  return err
+//	}
+//	return child.Wait()
+//
+// The wait is bounded by the [context.Context] passed to Shutdown and by
+// [WithAckTimeout]. The children that have not exited by then are killed, and
+// reported in [ShutdownReport.Killed].
+//
+// StartChild waits for cmd in the background, so the Wait method of cmd must
+// not be called; call [Child.Wait] instead.
+func StartChild(cmd *exec.Cmd) (*Child, error) {
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	child := &Child{
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+	childrenLock.Lock()
+	children = append(children, child)
+	childrenLock.Unlock()
+	go func() {
+		child.err = cmd.Wait()
+		childrenLock.Lock()
+		children = slices.DeleteFunc(children, func(other *Child) bool {
+			return other == child
+		})
+		childrenLock.Unlock()
+		close(child.done)
+	}()
+	return child, nil
+}
+
+// Wait waits for the child to exit, and returns the error returned by the
+// Wait method of its [exec.Cmd].
+func (c *Child) Wait() error {
+	<-c.done
+	return c.err
+}
+
+// Done returns a channel that is closed once the child has exited.
+func (c *Child) Done() <-chan struct{} {
+	return c.done
+}
+
+// String returns the name of the program of the child and its process ID.
+func (c *Child) String() string {
+	return fmt.Sprintf("%s (pid %d)", filepath.Base(c.cmd.Path), c.cmd.Process.Pid)
+}
+
+// waitChildren waits until all children started with StartChild have exited,
+// and kills the children that have not exited if ctx is done or timeout
+// elapses first, returning their names.
+func waitChildren(ctx context.Context, timeout time.Duration) []string {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	childrenLock.Lock()
+	pending := slices.Clone(children)
+	childrenLock.Unlock()
+	for i, child := range pending {
+		select {
+		case <-child.done:
+		case <-ctx.Done():
+			var killed []string
+			for _, child := range pending[i:] {
+				select {
+				case <-child.done:
+				default:
+					_ = child.cmd.Process.Kill()
+					killed = append(killed, child.String())
+				}
+			}
+			return killed
+		}
+	}
+	return nil
+}
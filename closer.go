@@ -0,0 +1,138 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// CloserGroup closes a group of [io.Closer]s, such as files, listeners, and
+// clients, when the program is interrupted.
+//
+// Closers are closed in the reverse order in which they were added. A
+// CloserGroup is safe for concurrent use.
+type CloserGroup struct {
+	timeout time.Duration
+
+	lock    sync.Mutex
+	closers []io.Closer
+	closing bool
+	done    chan struct{}
+	err     error
+}
+
+// NewCloserGroup returns a new [CloserGroup] that is closed once the context
+// returned by one of the Handle functions that ctx is derived from is done, as
+// with [Defer].
+//
+// If timeout is positive, closing is abandoned once the timeout elapses, and
+// the closers that have not been closed by then are reported as an error
+// matching [context.DeadlineExceeded].
+func NewCloserGroup(ctx context.Context, timeout time.Duration) *CloserGroup {
+	closerGroup := &CloserGroup{
+		timeout: timeout,
+		done:    make(chan struct{}),
+	}
+	Defer(ctx, func() {
+		_ = closerGroup.Close()
+	})
+	return closerGroup
+}
+
+// Add adds closer to the group.
+//
+// If the group is already closing, closer is closed immediately, and any error
+// is included in the error returned by [CloserGroup.Wait].
+func (g *CloserGroup) Add(closer io.Closer) {
+	g.lock.Lock()
+	if !g.closing {
+		defer g.lock.Unlock()
+		g.closers = append(g.closers, closer)
+		return
+	}
+	g.lock.Unlock()
+	// closer is closed without holding the lock, so that a slow closer does not
+	// block the other methods of the group.
+	err := closer.Close()
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.err = errors.Join(g.err, err)
+}
+
+// Close closes all closers in the group in the reverse order in which they were
+// added, and returns their errors joined with [errors.Join].
+//
+// Close is called automatically when the program is interrupted. If the group
+// is already closing, Close waits for closing to complete, as with
+// [CloserGroup.Wait].
+func (g *CloserGroup) Close() error {
+	g.lock.Lock()
+	if g.closing {
+		g.lock.Unlock()
+		return g.Wait()
+	}
+	g.closing = true
+	closers := g.closers
+	g.closers = nil
+	g.lock.Unlock()
+	err := closeAll(closers, g.timeout)
+	g.lock.Lock()
+	g.err = errors.Join(g.err, err)
+	g.lock.Unlock()
+	close(g.done)
+	return g.Wait()
+}
+
+// Wait blocks until the group has been closed, and returns the errors from
+// closing.
+func (g *CloserGroup) Wait() error {
+	<-g.done
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.err
+}
+
+func closeAll(closers []io.Closer, timeout time.Duration) error {
+	errs := make([]error, 0, len(closers))
+	var timeoutC <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		if timeoutC == nil {
+			errs = append(errs, closers[i].Close())
+			continue
+		}
+		errC := make(chan error, 1)
+		go func() {
+			errC <- closers[i].Close()
+		}()
+		select {
+		case err := <-errC:
+			errs = append(errs, err)
+		case <-timeoutC:
+			errs = append(errs, fmt.Errorf("%d closers abandoned: %w", i+1, context.DeadlineExceeded))
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(errs...)
+}
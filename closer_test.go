@@ -0,0 +1,100 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCloser is an io.Closer that records the order in which closers are
+// closed.
+type testCloser struct {
+	name   string
+	closed *[]string
+	lock   *sync.Mutex
+	err    error
+	blockC chan struct{}
+}
+
+func (c testCloser) Close() error {
+	if c.blockC != nil {
+		<-c.blockC
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	*c.closed = append(*c.closed, c.name)
+	return c.err
+}
+
+func TestCloserGroup(t *testing.T) {
+	resetGlobals(t)
+	var lock sync.Mutex
+	var closed []string
+	closeErr := errors.New("close failed")
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	closerGroup := NewCloserGroup(ctx, 0)
+	closerGroup.Add(testCloser{name: "first", closed: &closed, lock: &lock})
+	closerGroup.Add(testCloser{name: "second", closed: &closed, lock: &lock, err: closeErr})
+	Trigger(testInterrupt)
+	if err := closerGroup.Wait(); !errors.Is(err, closeErr) {
+		t.Errorf("Wait() = %v, want %v", err, closeErr)
+	}
+	// Closers added while closing are closed immediately.
+	closerGroup.Add(testCloser{name: "late", closed: &closed, lock: &lock})
+	lock.Lock()
+	defer lock.Unlock()
+	if want := []string{"second", "first", "late"}; !slices.Equal(closed, want) {
+		t.Errorf("closed %v, want %v", closed, want)
+	}
+}
+
+func TestCloserGroupTimeout(t *testing.T) {
+	resetGlobals(t)
+	var lock sync.Mutex
+	var closed []string
+	blockC := make(chan struct{})
+	defer close(blockC)
+	closerGroup := NewCloserGroup(context.Background(), 10*time.Millisecond)
+	closerGroup.Add(testCloser{name: "stuck", closed: &closed, lock: &lock, blockC: blockC})
+	if err := closerGroup.Close(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// A slow closer added while closing does not block the group.
+	addedC := make(chan struct{})
+	go func() {
+		defer close(addedC)
+		closerGroup.Add(testCloser{name: "slow", closed: &closed, lock: &lock, blockC: blockC})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	waitC := make(chan error, 1)
+	go func() {
+		waitC <- closerGroup.Close()
+	}()
+	select {
+	case <-waitC:
+	case <-time.After(testTimeout):
+		t.Fatal("Close blocked by a closer added while closing")
+	}
+	select {
+	case <-addedC:
+		t.Error("Add returned before closing the closer")
+	default:
+	}
+}
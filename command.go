@@ -0,0 +1,52 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// commandWaitDelay is the default WaitDelay of the commands returned by
+// Command.
+const commandWaitDelay = 10 * time.Second
+
+// Command returns an [exec.Cmd] to run the named program with the given
+// arguments, like [exec.CommandContext], except that the program is stopped
+// gracefully when ctx is done.
+//
+// When ctx is done, [SignalGracefully] asks the process to terminate, and if
+// it has not exited after the WaitDelay of the command, it is killed. The
+// WaitDelay defaults to 10 seconds, and can be changed on the returned command
+// before it is started:
+//
+//	cmd := interrupt.Command(ctx, "worker", "-v")
+//	cmd.WaitDelay = 30 * time.Second
+//	if err := cmd.Run(); err != nil {
+//	  return err
+//	}
+//
+// On Windows, the process is started in its own process group, as required by
+// SignalGracefully, so it no longer receives Ctrl+C from the console directly.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return SignalGracefully(cmd.Process)
+	}
+	cmd.WaitDelay = commandWaitDelay
+	prepareCommand(cmd)
+	return cmd
+}
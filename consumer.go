@@ -0,0 +1,61 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Consumer is a message consumer, such as a Kafka, NATS, or SQS worker, that
+// can be shut down without losing or double-processing messages.
+type Consumer interface {
+	// StopFetching stops fetching new messages.
+	StopFetching(ctx context.Context) error
+	// Drainer finishes processing the messages in flight.
+	Drainer
+	// Commit commits the offsets of, or acknowledges, the messages processed.
+	Commit(ctx context.Context) error
+}
+
+// RegisterConsumer registers consumer to be shut down by [Shutdown], by calling
+// its StopFetching, Drain, and Commit methods in order.
+//
+// StopFetching and Drain are bounded by timeout, if positive. Each method is
+// called even if the previous ones failed or timed out, and Commit is called
+// with the [context.Context] passed to the hook, not bounded by timeout, so
+// that the messages that were processed are committed:
+//
+//	interrupt.RegisterConsumer(consumer, 20*time.Second, interrupt.WithName("orders"))
+//
+// The consumer is registered as a hook with [OnShutdown] in [StageDrain], with
+// the given [HookOption]s, which may override the stage. The errors returned by
+// the methods are joined with [errors.Join].
+func RegisterConsumer(consumer Consumer, timeout time.Duration, opts ...HookOption) {
+	OnShutdown(func(ctx context.Context) error {
+		drainCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			drainCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return errors.Join(
+			consumer.StopFetching(drainCtx),
+			consumer.Drain(drainCtx),
+			consumer.Commit(ctx),
+		)
+	}, append([]HookOption{WithStage(StageDrain)}, opts...)...)
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+)
+
+// ServeControl serves control commands on a unix domain socket at path, until
+// ctx is done.
+//
+// This allows operators and scripts to drive a graceful stop where sending
+// signals is awkward, such as on Windows, or without access to the process.
+// Each line written to the socket is a command, to which ServeControl replies
+// with "ok" or "error: " followed by the error:
+//
+//   - "shutdown" requests a graceful shutdown with [RequestShutdown].
+//   - "drain" moves the program to [StateDraining], so that readiness checks
+//     fail and traffic is routed elsewhere, without marking contexts done.
+//   - "reload" delivers the first of the [ReloadSignals] with [Trigger].
+//
+// For example:
+//
+//	go interrupt.ServeControl(context.Background(), "/run/app/control.sock")
+//
+//	$ echo shutdown | nc -U /run/app/control.sock
+//	ok
+//
+// Anyone who can connect to the socket can shut the program down, so the
+// socket is made accessible only to the user that runs the program, with the
+// mode 0600, before commands are served. Since the mode is set once the socket
+// is created, path should be in a directory that only trusted users can
+// access, such as one created with the mode 0700.
+//
+// A stale socket at path is removed. The socket is removed once ctx is done.
+func ServeControl(ctx context.Context, path string) error {
+	if info, err := os.Lstat(path); err == nil && info.Mode().Type() == fs.ModeSocket {
+		_ = os.Remove(path)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = listener.Close()
+		return err
+	}
+	context.AfterFunc(ctx, func() {
+		// Closing a unix listener removes its socket.
+		_ = listener.Close()
+	})
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go serveControlConn(ctx, conn)
+	}
+}
+
+func serveControlConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stopAfterFunc := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stopAfterFunc()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		reply := "ok\n"
+		if err := control(strings.TrimSpace(scanner.Text())); err != nil {
+			reply = "error: " + err.Error() + "\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// control runs the given control command, as documented for ServeControl.
+func control(command string) error {
+	switch command {
+	case "shutdown":
+		RequestShutdown()
+	case "drain":
+		advanceState(StateDraining)
+	case "reload":
+		if len(ReloadSignals) == 0 {
+			return errors.New("reload is not supported on this platform")
+		}
+		Trigger(ReloadSignals[0])
+	default:
+		return errors.New("unknown command: " + command)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServeControl(t *testing.T) {
+	resetGlobals(t)
+	setReloadSignals(t, testReload)
+	path := filepath.Join(t.TempDir(), "control.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error, 1)
+	go func() {
+		errC <- ServeControl(ctx, path)
+	}()
+	var conn net.Conn
+	deadline := time.Now().Add(testTimeout)
+	for {
+		var err error
+		if conn, err = net.Dial("unix", path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	defer conn.Close()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("socket mode %v, want %v", perm, fs.FileMode(0o600))
+	}
+	reader := bufio.NewReader(conn)
+	command := func(command string) string {
+		t.Helper()
+		if _, err := conn.Write([]byte(command + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		reply, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		return reply
+	}
+	if reply := command("unknown"); reply != "error: unknown command: unknown\n" {
+		t.Errorf("reply %q to an unknown command", reply)
+	}
+	reloadCtx := HandleSignal(context.Background(), testReload)
+	if reply := command("reload"); reply != "ok\n" {
+		t.Errorf("reply %q to reload", reply)
+	}
+	waitDone(t, reloadCtx)
+	if reply := command("drain"); reply != "ok\n" {
+		t.Errorf("reply %q to drain", reply)
+	}
+	if state := ReadState(); state != StateDraining {
+		t.Errorf("ReadState() = %v after drain, want %v", state, StateDraining)
+	}
+	shutdownCtx := Handle(context.Background(), WithSignals(terminateSignal))
+	if reply := command("shutdown"); reply != "ok\n" {
+		t.Errorf("reply %q to shutdown", reply)
+	}
+	waitDone(t, shutdownCtx)
+	cancel()
+	if err := <-errC; err != nil {
+		t.Errorf("ServeControl() = %v once ctx is done, want nil", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("socket not removed once ctx is done: %v", err)
+	}
+}
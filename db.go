@@ -0,0 +1,63 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// dbPollInterval is the interval at which CloseDB checks whether connections
+// are still in use, as sql.DB provides no notification.
+const dbPollInterval = 10 * time.Millisecond
+
+// CloseDB closes db, which prevents new queries from starting, and then waits
+// until none of the connections of db are in use, or until ctx is done.
+//
+// Closing a [*sql.DB] does not wait for the queries in flight, whose
+// connections are only closed as they are returned. CloseDB gives in-flight
+// queries and transactions the chance to complete before returning, so that
+// the program does not exit while they are running. It is typically registered
+// as a hook in [StageClose], after the hooks of [StageDrain] have stopped new
+// work from starting, and bounded with [WithTimeout]:
+//
+//	interrupt.OnShutdown(func(ctx context.Context) error {
+//	  return interrupt.CloseDB(ctx, db)
+//	}, interrupt.WithStage(interrupt.StageClose), interrupt.WithTimeout(10*time.Second))
+//
+// If ctx is done before all connections are returned, CloseDB returns an error
+// matching the error of ctx.
+func CloseDB(ctx context.Context, db *sql.DB) error {
+	closeErr := db.Close()
+	ticker := time.NewTicker(dbPollInterval)
+	defer ticker.Stop()
+	for {
+		inUse := db.Stats().InUse
+		if inUse == 0 {
+			return closeErr
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.Join(
+				closeErr,
+				fmt.Errorf("%d database connections still in use: %w", inUse, ctx.Err()),
+			)
+		}
+	}
+}
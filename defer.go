@@ -0,0 +1,41 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import "context"
+
+// Defer arranges for fn to be called once the context returned by one of the
+// Handle functions that ctx is derived from is done, and returns a function to
+// cancel the call.
+//
+// This gives library code deep in the call stack a place to hang cleanup that
+// must happen when the program is interrupted, without plumbing new parameters:
+//
+//	func (c *Client) Start(ctx context.Context) {
+//	  interrupt.Defer(ctx, c.flush)
+//	  ...
+//	}
+//
+// Unlike [context.AfterFunc] with ctx, fn is not called when ctx is done for
+// reasons that do not affect the handled context, such as ctx timing out. If
+// ctx is not derived from a context returned by one of the Handle functions,
+// fn is called once ctx is done.
+//
+// fn is called at most once, in its own goroutine. Calling the returned stop
+// function prevents fn from being called if it has not been called yet, and
+// returns true if it did so, as with [context.AfterFunc].
+func Defer(ctx context.Context, fn func()) (stop func() bool) {
+	return context.AfterFunc(handledContext(ctx), fn)
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	dispatchLock sync.Mutex
+	// subscriptions contains all subscriptions registered with subscribe.
+	subscriptions = make(map[*subscription]struct{})
+	// channelSubscriptions contains the subscriptions for the channels
+	// registered with notify.
+	channelSubscriptions = make(map[chan<- os.Signal]*subscription)
+	// sources contains a channel registered with startSource for each signal
+	// that at least one subscription is registered for.
+	sources = make(map[os.Signal]*source)
+	// paused is the number of calls to Pause without a matching call to Resume.
+	paused int
+	// stats are the statistics returned by ReadStats.
+	stats = Stats{
+		Counts: make(map[os.Signal]int),
+	}
+)
+
+// subscription is a registration for signals delivered by [dispatch].
+//
+// Exactly one of c and f is set.
+type subscription struct {
+	signals []os.Signal
+	// c is sent signals without blocking while dispatchLock is held.
+	c chan<- os.Signal
+	// f is called with signals after dispatchLock is released.
+	f func(os.Signal)
+}
+
+type source struct {
+	c             chan os.Signal
+	stop          func()
+	subscriptions int
+}
+
+// notify is [os/signal.Notify], except that signals are delivered to c by
+// [dispatch].
+//
+// As with [os/signal.Notify], calling notify again for the same channel adds
+// the given signals to those delivered to it.
+func notify(c chan<- os.Signal, signals ...os.Signal) {
+	dispatchLock.Lock()
+	defer dispatchLock.Unlock()
+	previous, ok := channelSubscriptions[c]
+	if ok {
+		signals = append(slices.Clone(previous.signals), signals...)
+	}
+	sub := &subscription{
+		signals: signals,
+		c:       c,
+	}
+	channelSubscriptions[c] = sub
+	// The new subscription is registered before the previous one is
+	// unregistered, so that the sources of their common signals are kept.
+	subscribeLocked(sub)
+	if ok {
+		unsubscribeLocked(previous)
+	}
+}
+
+// stopNotify is [os/signal.Stop] for a channel registered with [notify].
+//
+// When stopNotify returns, it is guaranteed that c will receive no more signals.
+func stopNotify(c chan<- os.Signal) {
+	dispatchLock.Lock()
+	defer dispatchLock.Unlock()
+	if sub, ok := channelSubscriptions[c]; ok {
+		delete(channelSubscriptions, c)
+		unsubscribeLocked(sub)
+	}
+}
+
+// subscribe registers f to be called with each of the given signals that
+// arrives, and returns the subscription to pass to [unsubscribe].
+//
+// Calls to f are made from the goroutine receiving the signal, so f must not
+// block, and may be called concurrently with itself for different signals. f
+// may be called once more after unsubscribe returns.
+func subscribe(f func(os.Signal), signals ...os.Signal) *subscription {
+	sub := &subscription{
+		signals: signals,
+		f:       f,
+	}
+	dispatchLock.Lock()
+	defer dispatchLock.Unlock()
+	subscribeLocked(sub)
+	return sub
+}
+
+// unsubscribe unregisters a subscription returned by [subscribe].
+//
+// Once no subscription is registered for a signal, the default behavior of Go
+// programs for the signal is restored. Calling unsubscribe more than once has
+// no effect.
+func unsubscribe(sub *subscription) {
+	dispatchLock.Lock()
+	defer dispatchLock.Unlock()
+	unsubscribeLocked(sub)
+}
+
+// subscribeLocked registers sub.
+//
+// Signals are registered with [startSource] once for all subscriptions, so
+// that each signal that arrives is observed by the package exactly once.
+func subscribeLocked(sub *subscription) {
+	var uniqueSignals []os.Signal
+	for _, sig := range sub.signals {
+		if !slices.Contains(uniqueSignals, sig) {
+			uniqueSignals = append(uniqueSignals, sig)
+		}
+	}
+	sub.signals = uniqueSignals
+	subscriptions[sub] = struct{}{}
+	for _, sig := range sub.signals {
+		src, ok := sources[sig]
+		if !ok {
+			src = &source{
+				c: make(chan os.Signal, 1),
+			}
+			src.stop = startSource(src.c, sig)
+			sources[sig] = src
+			go func() {
+				for sig := range src.c {
+					dispatch(sig)
+				}
+			}()
+		}
+		src.subscriptions++
+	}
+}
+
+func unsubscribeLocked(sub *subscription) {
+	if _, ok := subscriptions[sub]; !ok {
+		return
+	}
+	delete(subscriptions, sub)
+	for _, sig := range sub.signals {
+		src := sources[sig]
+		src.subscriptions--
+		if src.subscriptions == 0 {
+			src.stop()
+			close(src.c)
+			delete(sources, sig)
+		}
+	}
+}
+
+// dispatch delivers sig to all subscriptions for sig, unless signal handling
+// is paused.
+func dispatch(sig os.Signal) {
+	var fs []func(os.Signal)
+	dispatchLock.Lock()
+	stats.record(sig, time.Now())
+	if paused == 0 {
+		for sub := range subscriptions {
+			if !slices.Contains(sub.signals, sig) {
+				continue
+			}
+			if sub.f != nil {
+				fs = append(fs, sub.f)
+				continue
+			}
+			select {
+			case sub.c <- sig:
+			default:
+			}
+		}
+	}
+	dispatchLock.Unlock()
+	record(func(recorder MetricsRecorder) {
+		recorder.SignalReceived(sig)
+	})
+	for _, f := range fs {
+		f(sig)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// receiveAll returns the signals received on c until none arrives for a short
+// time.
+func receiveAll(c <-chan os.Signal) []os.Signal {
+	var received []os.Signal
+	for {
+		select {
+		case sig := <-c:
+			received = append(received, sig)
+		case <-time.After(20 * time.Millisecond):
+			return received
+		}
+	}
+}
+
+func TestDispatchStats(t *testing.T) {
+	resetGlobals(t)
+	c := make(chan os.Signal, 4)
+	notify(c, testInterrupt, testReload)
+	defer stopNotify(c)
+	Trigger(testReload)
+	Trigger(testInterrupt)
+	Trigger(testInterrupt)
+	stats := ReadStats()
+	if stats.Total != 3 || stats.Counts[testInterrupt] != 2 || stats.Counts[testReload] != 1 {
+		t.Errorf("ReadStats() = %+v, want 2 %v and 1 %v", stats, testInterrupt, testReload)
+	}
+	if stats.First.Signal != testReload || stats.Last.Signal != testInterrupt {
+		t.Errorf("ReadStats() first %v and last %v, want %v and %v", stats.First.Signal, stats.Last.Signal, testReload, testInterrupt)
+	}
+}
+
+func TestPause(t *testing.T) {
+	resetGlobals(t)
+	c := make(chan os.Signal, 4)
+	notify(c, testInterrupt)
+	defer stopNotify(c)
+	Pause()
+	Trigger(testInterrupt)
+	Resume()
+	if received := receiveAll(c); len(received) != 0 {
+		t.Errorf("received %v while paused", received)
+	}
+	Trigger(testInterrupt)
+	if received := receiveAll(c); len(received) != 1 {
+		t.Errorf("received %v after Resume, want 1 signal", received)
+	}
+}
+
+func TestNotifySameChannel(t *testing.T) {
+	resetGlobals(t)
+	c := make(chan os.Signal, 4)
+	notify(c, testInterrupt)
+	notify(c, testInterrupt, testReload)
+	waitSubscriptions(t, 1)
+	Trigger(testInterrupt)
+	Trigger(testReload)
+	if received := receiveAll(c); len(received) != 2 || received[0] != testInterrupt || received[1] != testReload {
+		t.Errorf("received %v, want [%v %v]", received, testInterrupt, testReload)
+	}
+	stopNotify(c)
+	waitSubscriptions(t, 0)
+	Trigger(testInterrupt)
+	if received := receiveAll(c); len(received) != 0 {
+		t.Errorf("received %v after stopNotify", received)
+	}
+}
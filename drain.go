@@ -0,0 +1,152 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DrainTracker tracks in-flight work, and drains it when the program is
+// interrupted.
+//
+// Components call [DrainTracker.Begin] when starting a unit of work, such as a
+// request or a message, and call the returned end function when the work is
+// complete. When the program is interrupted, the tracker stops accepting new
+// work, waits for in-flight work to complete up to a timeout, and then marks
+// the context returned by [DrainTracker.Context] done, signaling that any
+// remaining work should be aborted:
+//
+//	tracker := interrupt.NewDrainTracker(ctx, 30*time.Second)
+//	...
+//	end, ok := tracker.Begin()
+//	if !ok {
+//	  return errUnavailable
+//	}
+//	defer end()
+//	return process(tracker.Context(), request)
+//
+// A DrainTracker is safe for concurrent use.
+type DrainTracker struct {
+	timeout    time.Duration
+	hardCtx    context.Context
+	hardCancel context.CancelCauseFunc
+
+	lock      sync.Mutex
+	accepting bool
+	inFlight  int
+	// idleC is closed once the tracker is not accepting and no work is in
+	// flight.
+	idleC chan struct{}
+}
+
+// NewDrainTracker returns a new [DrainTracker] that drains once the context
+// returned by one of the Handle functions that ctx is derived from is done, as
+// with [Defer].
+//
+// If timeout is positive, in-flight work is waited for up to the timeout. The
+// context returned by [DrainTracker.Context] retains the values of ctx.
+func NewDrainTracker(ctx context.Context, timeout time.Duration) *DrainTracker {
+	hardCtx, hardCancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	tracker := &DrainTracker{
+		timeout:    timeout,
+		hardCtx:    hardCtx,
+		hardCancel: hardCancel,
+		accepting:  true,
+		idleC:      make(chan struct{}),
+	}
+	Defer(ctx, func() {
+		drainCtx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			drainCtx, cancel = context.WithTimeout(drainCtx, timeout)
+			defer cancel()
+		}
+		hardCancel(tracker.Drain(drainCtx))
+	})
+	return tracker
+}
+
+// Begin begins a unit of work, and returns a function to call when the work is
+// complete.
+//
+// If the tracker is no longer accepting work, Begin returns false, and the
+// work should be rejected. The returned function may be called more than once.
+func (t *DrainTracker) Begin() (end func(), ok bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.accepting {
+		return func() {}, false
+	}
+	t.inFlight++
+	return sync.OnceFunc(t.end), true
+}
+
+// Accepting returns true if the tracker is accepting new work.
+func (t *DrainTracker) Accepting() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.accepting
+}
+
+// InFlight returns the number of units of work in flight.
+func (t *DrainTracker) InFlight() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.inFlight
+}
+
+// Context returns a [context.Context] for in-flight work, which is marked done
+// once draining completes or times out.
+//
+// If draining timed out, the cause of the context is an error matching
+// [context.DeadlineExceeded].
+func (t *DrainTracker) Context() context.Context {
+	return t.hardCtx
+}
+
+// Drain stops accepting new work, and waits until no work is in flight or ctx
+// is done.
+//
+// Drain is called automatically when the program is interrupted, but may also
+// be called directly. If ctx is done before in-flight work completes, Drain
+// returns an error matching the error of ctx.
+func (t *DrainTracker) Drain(ctx context.Context) error {
+	t.lock.Lock()
+	if t.accepting {
+		t.accepting = false
+		if t.inFlight == 0 {
+			close(t.idleC)
+		}
+	}
+	t.lock.Unlock()
+	select {
+	case <-t.idleC:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d units of work still in flight: %w", t.InFlight(), ctx.Err())
+	}
+}
+
+func (t *DrainTracker) end() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.inFlight--
+	if !t.accepting && t.inFlight == 0 {
+		close(t.idleC)
+	}
+}
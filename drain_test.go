@@ -0,0 +1,76 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDrainTracker(t *testing.T) {
+	resetGlobals(t)
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	tracker := NewDrainTracker(ctx, 0)
+	end, ok := tracker.Begin()
+	if !ok {
+		t.Fatal("Begin() = false before an interrupt")
+	}
+	if inFlight := tracker.InFlight(); inFlight != 1 {
+		t.Errorf("InFlight() = %d, want 1", inFlight)
+	}
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	deadline := time.Now().Add(testTimeout)
+	for tracker.Accepting() {
+		if time.Now().After(deadline) {
+			t.Fatal("tracker still accepting after an interrupt")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, ok := tracker.Begin(); ok {
+		t.Error("Begin() = true while draining")
+	}
+	// In-flight work keeps running until it ends.
+	assertNotDone(t, tracker.Context())
+	end()
+	end()
+	waitDone(t, tracker.Context())
+	if err := context.Cause(tracker.Context()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Cause() = %v, want %v", err, context.Canceled)
+	}
+	if inFlight := tracker.InFlight(); inFlight != 0 {
+		t.Errorf("InFlight() = %d, want 0", inFlight)
+	}
+}
+
+func TestDrainTrackerTimeout(t *testing.T) {
+	resetGlobals(t)
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	tracker := NewDrainTracker(ctx, 10*time.Millisecond)
+	end, _ := tracker.Begin()
+	defer end()
+	Trigger(testInterrupt)
+	waitDone(t, tracker.Context())
+	if err := context.Cause(tracker.Context()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Cause() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	drainCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tracker.Drain(drainCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	drainersLock sync.Mutex
+	// drainers contains the drainers registered with RegisterDrainer that are
+	// drained by drainHook.
+	drainers []Drainer
+	// drainHook is the hook that drains the drainers, if it was registered.
+	drainHook *hook
+)
+
+// Drainer is a component that can be drained, such as a cache, a queue, or a
+// connection pool.
+//
+// [*DrainTracker] and [*Listener] are Drainers.
+type Drainer interface {
+	// Drain completes in-flight work, and returns once it has completed or ctx
+	// is done.
+	Drain(ctx context.Context) error
+}
+
+// RegisterDrainer registers drainer to be drained by [Shutdown].
+//
+// All registered drainers are drained concurrently, from a single hook in
+// [StageDrain], so that the time that they take is bounded by the slowest of
+// them rather than their sum. This provides a single integration point for
+// third-party clients:
+//
+//	interrupt.RegisterDrainer(cache)
+//	interrupt.RegisterDrainer(queue)
+//
+// The [context.Context] passed to Drain is the one passed to Shutdown. The
+// errors returned by the drainers are joined with [errors.Join]. Each drainer
+// is drained at most once.
+func RegisterDrainer(drainer Drainer) {
+	drainersLock.Lock()
+	defer drainersLock.Unlock()
+	// The hook and its drainers remain registered after a Shutdown with
+	// WithDryRun. Otherwise, the drainers of the hook have been drained.
+	if drainHook == nil || !drainHook.registered() {
+		drainers = nil
+		drainHook = onShutdown(drainAll, WithStage(StageDrain), WithName("drainers"))
+	}
+	drainers = append(drainers, drainer)
+}
+
+// drainAll drains all registered drainers concurrently.
+func drainAll(ctx context.Context) error {
+	drainersLock.Lock()
+	takenDrainers := drainers
+	drainersLock.Unlock()
+	errs := make([]error, len(takenDrainers))
+	var wg sync.WaitGroup
+	for i, drainer := range takenDrainers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = drainer.Drain(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+type testDrainer struct {
+	drained atomic.Int32
+}
+
+func (d *testDrainer) Drain(context.Context) error {
+	d.drained.Add(1)
+	return nil
+}
+
+func TestRegisterDrainer(t *testing.T) {
+	resetGlobals(t)
+	var first, second testDrainer
+	RegisterDrainer(&first)
+	if err := Shutdown(context.Background(), WithDryRun()); err != nil {
+		t.Fatal(err)
+	}
+	RegisterDrainer(&second)
+	if err := Shutdown(context.Background(), WithDryRun()); err != nil {
+		t.Fatal(err)
+	}
+	report, _ := ReadShutdownReport()
+	if len(report.Hooks) != 1 {
+		t.Errorf("%d hooks after a dry run, want 1", len(report.Hooks))
+	}
+	if n, m := first.drained.Load(), second.drained.Load(); n != 2 || m != 1 {
+		t.Errorf("drained %d and %d times by dry runs, want 2 and 1", n, m)
+	}
+	// A dry run leaves no trace, so the real shutdown drains every drainer.
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n, m := first.drained.Load(), second.drained.Load(); n != 3 || m != 2 {
+		t.Errorf("drained %d and %d times, want 3 and 2", n, m)
+	}
+	// Drainers are drained at most once by real shutdowns.
+	var third testDrainer
+	RegisterDrainer(&third)
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n, m, o := first.drained.Load(), second.drained.Load(), third.drained.Load(); n != 3 || m != 2 || o != 1 {
+		t.Errorf("drained %d, %d, and %d times, want 3, 2, and 1", n, m, o)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrInterrupted is the error that a [*SignalError] matches with [errors.Is].
+//
+// It allows code that receives the cause of cancellation of a context returned
+// by one of the Handle functions, possibly wrapped, to distinguish an interrupt
+// from a genuine failure:
+//
+//	if err := run(ctx); err != nil {
+//	  if errors.Is(context.Cause(ctx), interrupt.ErrInterrupted) {
+//	    // The user asked us to stop.
+//	  }
+//	}
+//
+// Note that the Err method of a [context.Context] always returns
+// [context.Canceled], so use [context.Cause] to retrieve a [*SignalError].
+var ErrInterrupted = errors.New("interrupted")
+
+// SignalError is the cause of cancellation of a [context.Context] returned by
+// one of the Handle functions when a signal arrives.
+type SignalError struct {
+	// Signal is the signal that was received.
+	Signal os.Signal
+	// Time is the time that the signal was received.
+	Time time.Time
+}
+
+// Error implements error.
+func (e *SignalError) Error() string {
+	return "received signal: " + e.Signal.String()
+}
+
+// Is reports whether target is [ErrInterrupted].
+func (e *SignalError) Is(target error) bool {
+	return target == ErrInterrupted
+}
+
+// IsInterrupted reports whether err matches [ErrInterrupted].
+//
+// This is shorthand for errors.Is(err, interrupt.ErrInterrupted).
+func IsInterrupted(err error) bool {
+	return errors.Is(err, ErrInterrupted)
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// escalationWaitMargin is added to the WaitDelay set by Escalation.Apply, so
+// that the last step is taken before Wait kills the process itself.
+const escalationWaitMargin = time.Second
+
+// EscalationStep is a step of an [Escalation].
+type EscalationStep struct {
+	// Signal is the signal sent to the process.
+	Signal os.Signal
+	// Timeout is how long to wait for the process to exit before the next
+	// step.
+	Timeout time.Duration
+}
+
+// Escalation is a policy for stopping a child process by sending it signals of
+// increasing severity, for programs that do not exit on the first one.
+type Escalation struct {
+	// Steps are the steps taken in order, until the process has exited.
+	Steps []EscalationStep
+	// OnStep, if not nil, is called after the signal of each step has been
+	// sent to the process.
+	OnStep func(process *os.Process, step EscalationStep)
+}
+
+// NewEscalation returns an [Escalation] that asks a process to terminate, as
+// with [SignalGracefully], and kills it if it has not exited after timeout.
+func NewEscalation(timeout time.Duration) *Escalation {
+	return &Escalation{
+		Steps: []EscalationStep{
+			{Signal: terminateSignal, Timeout: timeout},
+			{Signal: os.Kill},
+		},
+	}
+}
+
+// Apply configures cmd to be stopped with the escalation when the context of
+// cmd is done, as created with [exec.CommandContext], [Command], or
+// [GroupCommand].
+//
+// If cmd starts the program in its own process group, as with
+// [SetProcessGroup] or [GroupCommand], the signals are sent to all processes
+// in the group, as with [GroupCommand]. Otherwise, they are sent to the
+// program only. On Windows, they are always sent to the program only.
+//
+// This sets the WaitDelay of cmd to the sum of the timeouts of the steps, plus
+// one second, so that the last step is taken before Wait gives up on cmd:
+//
+//	cmd := interrupt.Command(ctx, "third-party-daemon")
+//	escalation := interrupt.NewEscalation(30 * time.Second)
+//	escalation.OnStep = func(process *os.Process, step interrupt.EscalationStep) {
+//	  logger.Info("stopping", "pid", process.Pid, "signal", step.Signal)
+//	}
+//	escalation.Apply(cmd)
+func (e *Escalation) Apply(cmd *exec.Cmd) {
+	waitDelay := escalationWaitMargin
+	for _, step := range e.Steps {
+		waitDelay += step.Timeout
+	}
+	cmd.WaitDelay = waitDelay
+	cmd.Cancel = func() error {
+		if len(e.Steps) == 0 {
+			return nil
+		}
+		process := cmd.Process
+		signal := func(sig os.Signal) error {
+			return signalProcess(process, sig)
+		}
+		if startsProcessGroup(cmd) {
+			signal = func(sig os.Signal) error {
+				return signalLeaderGroup(process, sig)
+			}
+		}
+		if err := e.step(process, signal, e.Steps[0]); err != nil {
+			return err
+		}
+		go func() {
+			_ = e.stop(process, signal, e.Steps[0].Timeout, e.Steps[1:])
+		}()
+		return nil
+	}
+}
+
+// Stop takes the steps of the escalation for process in order, and returns
+// once the last step has been taken. The remaining steps are skipped once the
+// process has exited.
+//
+// An exited process is only detected once it has been waited for, so process
+// must be waited for concurrently, for example with [exec.Cmd.Wait].
+func (e *Escalation) Stop(process *os.Process) error {
+	return e.stop(process, func(sig os.Signal) error {
+		return signalProcess(process, sig)
+	}, 0, e.Steps)
+}
+
+// stop takes steps after delay, sending their signals with signal.
+func (e *Escalation) stop(process *os.Process, signal func(os.Signal) error, delay time.Duration, steps []EscalationStep) error {
+	for _, step := range steps {
+		time.Sleep(delay)
+		if err := e.step(process, signal, step); err != nil {
+			if errors.Is(err, os.ErrProcessDone) {
+				return nil
+			}
+			return err
+		}
+		delay = step.Timeout
+	}
+	return nil
+}
+
+func (e *Escalation) step(process *os.Process, signal func(os.Signal) error, step EscalationStep) error {
+	if err := signal(step.Signal); err != nil {
+		return err
+	}
+	if e.OnStep != nil {
+		e.OnStep(process, step)
+	}
+	return nil
+}
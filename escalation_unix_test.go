@@ -0,0 +1,110 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// stepRecorder records the signals of the steps that an Escalation takes.
+type stepRecorder chan os.Signal
+
+func (r stepRecorder) onStep(_ *os.Process, step EscalationStep) {
+	r <- step.Signal
+}
+
+// signals returns the signals of the first n steps taken.
+func (r stepRecorder) signals(t *testing.T, n int) []os.Signal {
+	t.Helper()
+	var signals []os.Signal
+	for range n {
+		select {
+		case sig := <-r:
+			signals = append(signals, sig)
+		case <-time.After(testTimeout):
+			t.Fatalf("steps = %v, want %d steps", signals, n)
+		}
+	}
+	return signals
+}
+
+func TestEscalationApply(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Both processes ignore syscall.SIGTERM, so only the last step stops them.
+	cmd := Command(ctx, "sh", "-c", "trap '' TERM; sleep 60 >/dev/null & echo $!; wait")
+	SetProcessGroup(cmd)
+	steps := make(stepRecorder, 2)
+	escalation := &Escalation{
+		Steps: []EscalationStep{
+			{Signal: syscall.SIGTERM, Timeout: 50 * time.Millisecond},
+			{Signal: os.Kill},
+		},
+		OnStep: steps.onStep,
+	}
+	escalation.Apply(cmd)
+	if want := 50*time.Millisecond + escalationWaitMargin; cmd.WaitDelay != want {
+		t.Errorf("WaitDelay = %v, want %v", cmd.WaitDelay, want)
+	}
+	grandchild := startWithGrandchild(t, cmd)
+	cancel()
+	err := cmd.Wait()
+	if exit, ok := ChildExitFromError(err); !ok || exit.Signal != os.Kill {
+		t.Errorf("Wait() = %v, want termination by %v", err, os.Kill)
+	}
+	// The signals were sent to the process group.
+	waitExited(t, grandchild)
+	if got, want := steps.signals(t, 2), []os.Signal{syscall.SIGTERM, os.Kill}; !slices.Equal(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+}
+
+func TestEscalationStop(t *testing.T) {
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+	steps := make(stepRecorder, 2)
+	escalation := &Escalation{
+		Steps: []EscalationStep{
+			{Signal: syscall.SIGTERM, Timeout: 500 * time.Millisecond},
+			{Signal: os.Kill},
+		},
+		OnStep: steps.onStep,
+	}
+	if err := escalation.Stop(cmd.Process); err != nil {
+		t.Fatal(err)
+	}
+	err := <-waitErr
+	if exit, ok := ChildExitFromError(err); !ok || exit.Signal != syscall.SIGTERM {
+		t.Errorf("Wait() = %v, want termination by %v", err, syscall.SIGTERM)
+	}
+	// The process exited on the first step, so the second one was skipped.
+	if len(steps) != 1 || <-steps != syscall.SIGTERM {
+		t.Errorf("steps taken = %d, want only %v", len(steps), syscall.SIGTERM)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ReadChildExit returns the exit of the child process described by state.
+//
+// This decodes the platform-specific status of state, so that a child process
+// terminated by a signal can be told apart from a child process that exited
+// with a code, on all platforms.
+func ReadChildExit(state *os.ProcessState) ChildExit {
+	return ChildExit{
+		PID:      state.Pid(),
+		ExitCode: state.ExitCode(),
+		Signal:   exitSignal(state),
+	}
+}
+
+// ChildExitFromError returns the exit of the child process described by err,
+// and true, if err is or wraps an [*exec.ExitError], as returned by the Wait
+// and Run methods of [exec.Cmd]:
+//
+//	if exit, ok := interrupt.ChildExitFromError(cmd.Run()); ok {
+//	  logger.Warn("worker stopped", "status", exit)
+//	  os.Exit(exit.Code())
+//	}
+//
+// Otherwise, it returns false.
+func ChildExitFromError(err error) (ChildExit, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return ChildExit{}, false
+	}
+	return ReadChildExit(exitErr.ProcessState), true
+}
+
+// Code returns the exit code conventionally used by a program to propagate the
+// exit of the child process: the exit code of the child process, or if it was
+// terminated by a signal, the exit code returned by [ExitCode] for the signal.
+func (e ChildExit) Code() int {
+	if e.Signal != nil {
+		return ExitCode(e.Signal)
+	}
+	return e.ExitCode
+}
+
+// String returns a description of the exit, such as "exited with code 1" or
+// "terminated by signal terminated".
+func (e ChildExit) String() string {
+	if e.Signal != nil {
+		return fmt.Sprintf("terminated by signal %v", e.Signal)
+	}
+	return fmt.Sprintf("exited with code %d", e.ExitCode)
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"os"
+	"os/exec"
+)
+
+// RunForeground runs cmd as the foreground process group of terminal, and
+// makes the previous foreground process group, typically that of the program,
+// the foreground process group again once cmd has exited.
+//
+// The terminal driver delivers the signals for Ctrl+C, Ctrl+\, and Ctrl+Z to
+// the foreground process group, so an interactive child process, such as an
+// editor or a shell, receives them while it runs, and the program does not.
+// This is the same whether terminal is the controlling terminal of the program
+// or a pseudo-terminal:
+//
+//	if err := interrupt.RunForeground(os.Stdin, exec.Command(editor, path)); err != nil {
+//	  return err
+//	}
+//
+// Signals sent to the program by other means, such as syscall.SIGTERM, are
+// handled by the program as usual. If cmd is stopped, for example with Ctrl+Z,
+// RunForeground waits until it is continued and exits.
+//
+// On platforms without job control, such as Windows, where the console delivers
+// Ctrl+C to all processes attached to it, this runs cmd with signal handling
+// paused, as with [Paused].
+func RunForeground(terminal *os.File, cmd *exec.Cmd) error {
+	return runForeground(terminal, cmd)
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd
+
+package interrupt
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+func runForeground(terminal *os.File, cmd *exec.Cmd) error {
+	// The file descriptor is used through SyscallConn rather than Fd, which
+	// would put terminal into blocking mode.
+	rawConn, err := terminal.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ctty int
+	var pgid int32
+	var errno syscall.Errno
+	if err := rawConn.Control(func(fd uintptr) {
+		ctty = int(fd)
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGPGRP, uintptr(unsafe.Pointer(&pgid)))
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Foreground = true
+	cmd.SysProcAttr.Ctty = ctty
+	err = cmd.Run()
+	// The program is in a background process group until the foreground is
+	// restored, so syscall.SIGTTOU must be ignored for the terminal driver to
+	// allow the change.
+	ignored := signal.Ignored(syscall.SIGTTOU)
+	if !ignored {
+		signal.Ignore(syscall.SIGTTOU)
+	}
+	controlErr := rawConn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCSPGRP, uintptr(unsafe.Pointer(&pgid)))
+	})
+	if !ignored {
+		signal.Reset(syscall.SIGTTOU)
+	}
+	switch {
+	case err != nil:
+		return err
+	case controlErr != nil:
+		return controlErr
+	case errno != 0:
+		return os.NewSyscallError("ioctl", errno)
+	}
+	return nil
+}
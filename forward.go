@@ -0,0 +1,111 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// Forwarder relays signals to child processes and process groups, so that a
+// program that launches other programs passes signals such as Ctrl+C through
+// to them instead of exiting while they keep running.
+//
+// Forwarder is created with [Forward].
+type Forwarder struct {
+	lock      sync.Mutex
+	processes map[*os.Process]struct{}
+	groups    map[int]struct{}
+}
+
+// Forward relays the given signals, or [Signals] if none are given, to the
+// processes and process groups added to the returned [Forwarder], until ctx is
+// done.
+//
+// While ctx is not done, the given signals no longer terminate the program.
+// A child started in the process group of the program also receives the
+// signals that the terminal generates, such as the one for Ctrl+C, directly,
+// so relaying them would deliver them twice, which many programs treat as a
+// request to exit immediately. Children should therefore be started in their
+// own process group, with [SetProcessGroup]:
+//
+//	forwarder := interrupt.Forward(ctx)
+//	cmd := exec.Command("worker")
+//	interrupt.SetProcessGroup(cmd)
+//	if err := cmd.Start(); err != nil {
+//	  return err
+//	}
+//	remove := forwarder.AddGroup(cmd.Process.Pid)
+//	defer remove()
+//	return cmd.Wait()
+//
+// On Windows, where only os.Kill can be sent to other processes, other signals
+// are relayed with a CTRL_BREAK_EVENT, as with [SignalGracefully]. Errors, for
+// example for processes that have already exited, are ignored.
+func Forward(ctx context.Context, signals ...os.Signal) *Forwarder {
+	forwarder := &Forwarder{
+		processes: make(map[*os.Process]struct{}),
+		groups:    make(map[int]struct{}),
+	}
+	if len(signals) == 0 {
+		signals = Signals
+	}
+	onSignal(ctx, forwarder.forward, signals)
+	return forwarder
+}
+
+// Add adds process to the processes that signals are relayed to, and returns
+// a function that removes it.
+func (f *Forwarder) Add(process *os.Process) (remove func()) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.processes[process] = struct{}{}
+	return func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		delete(f.processes, process)
+	}
+}
+
+// AddGroup adds the process group with the given ID to the process groups that
+// signals are relayed to, and returns a function that removes it.
+//
+// On unix-like platforms, signals are sent to all processes in the group, which
+// includes grandchildren such as the stages of a shell pipeline. On Windows,
+// the group must have been created with syscall.CREATE_NEW_PROCESS_GROUP, and
+// its ID is the process ID of its first process. On other platforms, signals
+// cannot be relayed to process groups.
+func (f *Forwarder) AddGroup(pgid int) (remove func()) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.groups[pgid] = struct{}{}
+	return func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		delete(f.groups, pgid)
+	}
+}
+
+func (f *Forwarder) forward(sig os.Signal) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for process := range f.processes {
+		_ = signalProcess(process, sig)
+	}
+	for pgid := range f.groups {
+		_ = signalProcessGroup(pgid, sig)
+	}
+}
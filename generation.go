@@ -0,0 +1,88 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"sync"
+)
+
+// Generations issues a [context.Context] for each generation of the
+// configuration of a program, so that components holding resources that
+// depend on the configuration, such as watchers or connections, can rebuild
+// them on reload.
+//
+// Generations is created with [NewGenerations].
+type Generations struct {
+	lock       sync.Mutex
+	ctx        context.Context
+	current    context.Context
+	cancel     context.CancelCauseFunc
+	generation int
+}
+
+// NewGenerations returns a new [Generations] whose contexts derive from ctx,
+// starting at generation 0.
+//
+// Whenever [Reload] is called, such as by [HandleReload] when syscall.SIGHUP
+// arrives, the context of the current generation is canceled with [ErrReload]
+// as the cause, and a new generation starts:
+//
+//	generations := interrupt.NewGenerations(ctx)
+//	interrupt.HandleReload(ctx)
+//	for ctx.Err() == nil {
+//	  generationCtx, _ := generations.Current()
+//	  watcher := startWatcher(generationCtx, loadConfig())
+//	  <-generationCtx.Done()
+//	  watcher.Close()
+//	}
+//
+// The context of the current generation is canceled when ctx is done, and no
+// new generation starts afterwards.
+func NewGenerations(ctx context.Context) *Generations {
+	g := &Generations{
+		ctx: ctx,
+	}
+	g.current, g.cancel = context.WithCancelCause(ctx)
+	remove := OnReload(func(context.Context) error {
+		g.Next()
+		return nil
+	})
+	context.AfterFunc(ctx, remove)
+	return g
+}
+
+// Current returns the context and the number of the current generation.
+func (g *Generations) Current() (context.Context, int) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.current, g.generation
+}
+
+// Next cancels the context of the current generation with [ErrReload] as the
+// cause, and starts a new generation.
+//
+// Calling Next once the context passed to NewGenerations is done has no
+// effect.
+func (g *Generations) Next() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.ctx.Err() != nil {
+		return
+	}
+	g.cancel(ErrReload)
+	g.current, g.cancel = context.WithCancelCause(g.ctx)
+	g.generation++
+}
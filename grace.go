@@ -0,0 +1,112 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// HandleWithGrace is like [Handle], but once an interrupt signal arrives, the
+// returned [context.Context] also gains a deadline of the given grace period
+// after the arrival of the signal.
+//
+// The deadline is reported by the Deadline method of the returned context and
+// any context derived from it, giving shutdown code a natural time budget:
+//
+//	ctx := interrupt.HandleWithGrace(context.Background(), 30*time.Second)
+//	...
+//	<-ctx.Done()
+//	deadline, _ := ctx.Deadline()
+//	shutdownCtx, cancel := context.WithDeadline(context.WithoutCancel(ctx), deadline)
+//	defer cancel()
+//	server.Shutdown(shutdownCtx)
+//
+// If the parent context has an earlier deadline, that deadline is reported
+// instead. HandleWithGrace accepts the same [Option]s as [Handle].
+func HandleWithGrace(ctx context.Context, gracePeriod time.Duration, opts ...Option) context.Context {
+	return &graceContext{
+		Context:     Handle(ctx, opts...),
+		gracePeriod: gracePeriod,
+	}
+}
+
+// HandlePhases returns two copies of the parent [context.Context] for a
+// two-phase shutdown.
+//
+// The drain context is marked done when the first interrupt signal arrives, as
+// with [Handle]. The hard context is marked done when the second interrupt
+// signal arrives, or when the grace period has elapsed after the first signal,
+// whichever happens first. Both are marked done when the parent Context's Done
+// channel is closed. This allows servers to stop accepting new work when the
+// drain context is done, and abort in-flight work when the hard context is
+// done:
+//
+//	drainCtx, hardCtx := interrupt.HandlePhases(context.Background(), 30*time.Second)
+//	go acceptWork(drainCtx)
+//	doWork(hardCtx)
+//
+// If the grace period elapses, the cause of the hard context is
+// [context.DeadlineExceeded]. Otherwise, the cause is a [*SignalError] for the
+// second signal.
+//
+// Since the second signal is handled, the program exits on the third signal by
+// default. This can be adjusted with [WithSignalThreshold]. HandlePhases accepts
+// the same [Option]s as [Handle].
+func HandlePhases(ctx context.Context, gracePeriod time.Duration, opts ...Option) (drainCtx context.Context, hardCtx context.Context) {
+	hardCtx, hardCancel := context.WithCancelCause(ctx)
+	options := newOptions(append([]Option{WithSignalThreshold(3)}, opts...))
+	options.shutdown = true
+	options.onSignal = append(options.onSignal, func(sig os.Signal, count int) {
+		if count == 2 {
+			hardCancel(&SignalError{Signal: sig, Time: time.Now()})
+		}
+	})
+	drainCtx, _ = handle(ctx, options)
+	context.AfterFunc(drainCtx, func() {
+		if !IsInterrupted(context.Cause(drainCtx)) {
+			hardCancel(nil)
+			return
+		}
+		timer := time.AfterFunc(gracePeriod, func() {
+			hardCancel(context.DeadlineExceeded)
+		})
+		context.AfterFunc(hardCtx, func() {
+			timer.Stop()
+		})
+	})
+	return drainCtx, hardCtx
+}
+
+type graceContext struct {
+	context.Context
+
+	gracePeriod time.Duration
+}
+
+func (c *graceContext) Deadline() (time.Time, bool) {
+	deadline, ok := c.Context.Deadline()
+	var signalError *SignalError
+	if !errors.As(context.Cause(c.Context), &signalError) {
+		return deadline, ok
+	}
+	graceDeadline := signalError.Time.Add(c.gracePeriod)
+	if ok && deadline.Before(graceDeadline) {
+		return deadline, true
+	}
+	return graceDeadline, true
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"sync"
+)
+
+// Group is a collection of goroutines working on subtasks of a common task,
+// with the semantics of golang.org/x/sync/errgroup, combined with interrupt
+// handling.
+//
+// The context of the group is canceled when an interrupt signal arrives, the
+// first time a function passed to [Group.Go] returns an error, or when
+// [Group.Wait] returns, whichever occurs first. [Group.Wait] distinguishes a
+// failed subtask from an interrupt:
+//
+//	group, ctx := interrupt.NewGroup(context.Background())
+//	group.Go(func() error { return serve(ctx) })
+//	group.Go(func() error { return consume(ctx) })
+//	if err := group.Wait(); err != nil {
+//	  if interrupt.IsInterrupted(err) {
+//	    // We were interrupted.
+//	  }
+//	  // A subtask failed.
+//	}
+type Group struct {
+	handledCtx context.Context
+	stop       context.CancelFunc
+	cancel     context.CancelCauseFunc
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup returns a new [Group], and the context of the group, which is a copy
+// of ctx that handles interrupt signals as with [Handle].
+//
+// NewGroup accepts the same [Option]s as [Handle].
+func NewGroup(ctx context.Context, opts ...Option) (*Group, context.Context) {
+	handledCtx, stop := HandleWithStop(ctx, opts...)
+	ctx, cancel := context.WithCancelCause(handledCtx)
+	return &Group{
+		handledCtx: handledCtx,
+		stop:       stop,
+		cancel:     cancel,
+	}, ctx
+}
+
+// Go calls fn in a new goroutine.
+//
+// The first call to return a non-nil error cancels the group's context, and
+// its error is returned by [Group.Wait].
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel(err)
+			})
+		}
+	}()
+}
+
+// Wait blocks until all function calls from [Group.Go] have returned, and then
+// returns the first error from them, if any.
+//
+// If an interrupt signal arrived, and no function returned an error other than
+// one matching [context.Canceled], Wait returns the [*SignalError] for the
+// signal, which matches [ErrInterrupted], as with [Run]. Signal handling is
+// unregistered before Wait returns, so that later interrupt signals result in
+// the default behavior.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(nil)
+	g.stop()
+	return interruptedError(g.handledCtx, g.err)
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	resetGlobals(t)
+	group, ctx := NewGroup(context.Background(), WithSignals(testInterrupt))
+	for range 3 {
+		group.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	Trigger(testInterrupt)
+	err := group.Wait()
+	var signalErr *SignalError
+	if !errors.As(err, &signalErr) || signalErr.Signal != testInterrupt || !errors.Is(err, ErrInterrupted) {
+		t.Errorf("Wait() = %v, want the error for %v", err, testInterrupt)
+	}
+	waitSubscriptions(t, 0)
+}
+
+func TestGroupError(t *testing.T) {
+	resetGlobals(t)
+	group, ctx := NewGroup(context.Background(), WithSignals(testInterrupt))
+	fnErr := errors.New("failed")
+	group.Go(func() error {
+		return fnErr
+	})
+	group.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := group.Wait(); !errors.Is(err, fnErr) {
+		t.Errorf("Wait() = %v, want %v", err, fnErr)
+	}
+	waitSubscriptions(t, 0)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Handler handles interrupt signals with a fixed set of [Option]s.
+//
+// A Handler allows interrupt handling to be configured once and injected into
+// the components of a program, as opposed to each component calling the
+// package-level functions with its own options. All signal handling registered
+// through a Handler can be stopped at once with [Handler.Stop].
+//
+// A Handler is safe for concurrent use.
+type Handler struct {
+	opts    []Option
+	options *options
+
+	lock    sync.Mutex
+	stops   []func()
+	stopped bool
+}
+
+// New returns a new [Handler] that uses the given [Option]s.
+func New(opts ...Option) *Handler {
+	return &Handler{
+		opts:    slices.Clone(opts),
+		options: newOptions(opts),
+	}
+}
+
+// Handle is [Handle] with the Handler's [Option]s.
+//
+// If the Handler was stopped, the returned [context.Context] is already done.
+func (h *Handler) Handle(ctx context.Context) context.Context {
+	ctx, stop := HandleWithStop(ctx, h.opts...)
+	h.addStop(stop)
+	return ctx
+}
+
+// OnSignal calls f with each signal handled by the Handler that arrives, until
+// the Handler is stopped.
+//
+// Calls to f are made sequentially from a single goroutine. See [OnInterrupt]
+// for details.
+func (h *Handler) OnSignal(f func(os.Signal)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.addStop(cancel)
+	onSignal(ctx, f, h.options.signals)
+}
+
+// Stop unregisters all signal handling registered through the Handler, and
+// marks all contexts returned by [Handler.Handle] done.
+//
+// Calling Stop more than once has no effect.
+func (h *Handler) Stop() {
+	h.lock.Lock()
+	stops := h.stops
+	h.stops = nil
+	h.stopped = true
+	h.lock.Unlock()
+	for _, stop := range stops {
+		stop()
+	}
+}
+
+func (h *Handler) addStop(stop func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.stopped {
+		stop()
+		return
+	}
+	h.stops = append(h.stops, stop)
+}
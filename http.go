@@ -0,0 +1,212 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServeHTTP serves HTTP with server on listener until ctx is done, and then
+// shuts server down gracefully.
+//
+// Once ctx is done, typically because it was returned by one of the Handle
+// functions and an interrupt signal arrived, ServeHTTP calls
+// [http.Server.Shutdown], which stops accepting connections and waits for
+// in-flight requests to complete. If gracePeriod is positive and in-flight
+// requests have not completed within it, ServeHTTP closes all connections with
+// [http.Server.Close]:
+//
+//	ctx := interrupt.Handle(context.Background())
+//	server := &http.Server{Handler: mux}
+//	if err := interrupt.ServeHTTP(ctx, server, nil, 25*time.Second); err != nil && !interrupt.IsInterrupted(err) {
+//	  log.Fatal(err)
+//	}
+//
+// If listener is nil, ServeHTTP listens on the TCP address server.Addr, or
+// ":http" if empty, as with [http.Server.ListenAndServe].
+//
+// If serving fails, ServeHTTP returns the error. If the shutdown did not
+// complete within gracePeriod, ServeHTTP returns an error matching
+// [context.DeadlineExceeded]. Otherwise, ServeHTTP returns the
+// [*SignalError] for the interrupt signal that arrived, if any, as with [Run],
+// or nil.
+func ServeHTTP(ctx context.Context, server *http.Server, listener net.Listener, gracePeriod time.Duration) error {
+	if listener == nil {
+		addr := server.Addr
+		if addr == "" {
+			addr = ":http"
+		}
+		var err error
+		listener, err = net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+	}
+	errC := make(chan error, 1)
+	go func() {
+		errC <- server.Serve(listener)
+	}()
+	select {
+	case err := <-errC:
+		if errors.Is(err, http.ErrServerClosed) {
+			// The server was shut down by other means.
+			return interruptedError(ctx, nil)
+		}
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx := context.WithoutCancel(ctx)
+	if gracePeriod > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, gracePeriod)
+		defer cancel()
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		_ = server.Close()
+		return fmt.Errorf("http server did not shut down gracefully: %w", err)
+	}
+	if err := <-errC; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return interruptedError(ctx, nil)
+}
+
+// MiddlewareOption is an option for [Middleware].
+type MiddlewareOption func(*middlewareOptions)
+
+// WithRetryAfter returns a new MiddlewareOption that sets the duration sent in
+// the Retry-After header of rejected requests, rounded up to whole seconds.
+//
+// The default is one second.
+func WithRetryAfter(retryAfter time.Duration) MiddlewareOption {
+	return func(middlewareOptions *middlewareOptions) {
+		middlewareOptions.retryAfter = retryAfter
+	}
+}
+
+// WithConnectionClose returns a new MiddlewareOption that sends a
+// "Connection: close" header with rejected requests, so that clients do not
+// reuse connections to a server that is shutting down.
+//
+// The default is to let the server and client decide.
+func WithConnectionClose() MiddlewareOption {
+	return func(middlewareOptions *middlewareOptions) {
+		middlewareOptions.connectionClose = true
+	}
+}
+
+// Middleware returns HTTP middleware that rejects new requests with
+// [http.StatusServiceUnavailable] once ctx is done.
+//
+// Requests that were already being handled when ctx became done are not
+// affected, so that in-flight handlers can complete while load balancers and
+// clients are told to retry elsewhere:
+//
+//	ctx := interrupt.Handle(context.Background(), interrupt.WithPreStopDelay(5*time.Second))
+//	server := &http.Server{Handler: interrupt.Middleware(ctx)(mux)}
+//
+// Rejected requests receive a Retry-After header, as set by [WithRetryAfter].
+func Middleware(ctx context.Context, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	middlewareOptions := &middlewareOptions{
+		retryAfter: time.Second,
+	}
+	for _, opt := range opts {
+		opt(middlewareOptions)
+	}
+	retryAfter := strconv.FormatInt(max(int64((middlewareOptions.retryAfter+time.Second-1)/time.Second), 0), 10)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if ctx.Err() == nil {
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+			header := responseWriter.Header()
+			header.Set("Retry-After", retryAfter)
+			if middlewareOptions.connectionClose {
+				header.Set("Connection", "close")
+			}
+			http.Error(responseWriter, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		})
+	}
+}
+
+type middlewareOptions struct {
+	retryAfter      time.Duration
+	connectionClose bool
+}
+
+// ReadinessHandler returns an [http.Handler] for readiness probes, such as
+// those of Kubernetes, that responds with [http.StatusOK] while the program is
+// in [StateReady], and with [http.StatusServiceUnavailable] once an interrupt
+// signal has been handled, so that traffic stops being routed to a program that
+// has begun draining:
+//
+//	mux.Handle("/readyz", interrupt.ReadinessHandler())
+//
+// The body of the response is the current [State].
+func ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		state := ReadState()
+		responseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		responseWriter.Header().Set("Cache-Control", "no-store")
+		if state != StateReady {
+			responseWriter.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = io.WriteString(responseWriter, state.String()+"\n")
+	})
+}
+
+// QuitHandler returns an [http.Handler] that requests a graceful shutdown with
+// [RequestShutdown] when it receives a POST request.
+//
+// This is useful on platforms where sending signals to the process is
+// awkward. The handler is opt-in and should be mounted on an admin listener:
+//
+//	adminMux.Handle("POST /-/quit", interrupt.QuitHandler(os.Getenv("QUIT_TOKEN")))
+//
+//	$ curl -X POST -H "Authorization: Bearer $QUIT_TOKEN" http://localhost:9090/-/quit
+//
+// Requests must carry token in an "Authorization: Bearer" header, or they are
+// rejected with [http.StatusUnauthorized]. If token is empty, all requests are
+// rejected, so that a missing token does not expose an unauthenticated
+// shutdown endpoint. Accepted requests receive [http.StatusAccepted], as the
+// shutdown proceeds asynchronously.
+func QuitHandler(token string) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			responseWriter.Header().Set("Allow", http.MethodPost)
+			http.Error(responseWriter, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		requestToken, ok := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(requestToken), []byte(token)) != 1 {
+			responseWriter.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(responseWriter, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		responseWriter.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(responseWriter, "shutting down\n")
+		RequestShutdown()
+	})
+}
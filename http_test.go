@@ -0,0 +1,60 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQuitHandler(t *testing.T) {
+	for _, testCase := range []struct {
+		name          string
+		token         string
+		method        string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "accepted", token: "secret", method: http.MethodPost, authorization: "Bearer secret", wantStatus: http.StatusAccepted},
+		{name: "get", token: "secret", method: http.MethodGet, authorization: "Bearer secret", wantStatus: http.StatusMethodNotAllowed},
+		{name: "missing", token: "secret", method: http.MethodPost, wantStatus: http.StatusUnauthorized},
+		{name: "wrong", token: "secret", method: http.MethodPost, authorization: "Bearer other", wantStatus: http.StatusUnauthorized},
+		{name: "basic", token: "secret", method: http.MethodPost, authorization: "Basic secret", wantStatus: http.StatusUnauthorized},
+		{name: "empty token", method: http.MethodPost, authorization: "Bearer ", wantStatus: http.StatusUnauthorized},
+		{name: "empty token without authorization", method: http.MethodPost, wantStatus: http.StatusUnauthorized},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			resetGlobals(t)
+			ctx, stop := HandleWithStop(context.Background(), WithSignals(terminateSignal))
+			defer stop()
+			request := httptest.NewRequest(testCase.method, "/-/quit", nil)
+			if testCase.authorization != "" {
+				request.Header.Set("Authorization", testCase.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			QuitHandler(testCase.token).ServeHTTP(recorder, request)
+			if recorder.Code != testCase.wantStatus {
+				t.Errorf("status %d, want %d", recorder.Code, testCase.wantStatus)
+			}
+			if testCase.wantStatus == http.StatusAccepted {
+				waitDone(t, ctx)
+			} else {
+				assertNotDone(t, ctx)
+			}
+		})
+	}
+}
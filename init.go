@@ -0,0 +1,87 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Init runs cmd as a minimal init process, as used for the entrypoint of a
+// container, and exits the program with the exit code of cmd.
+//
+// While cmd is running, Init forwards the signals that a container runtime
+// or a terminal sends to cmd, including syscall.SIGTERM, syscall.SIGINT, and
+// syscall.SIGHUP, and reaps all child processes with [Reap], including the
+// orphaned processes adopted by a program running as PID 1:
+//
+//	func main() {
+//	  interrupt.Init(exec.Command(os.Args[1], os.Args[2:]...))
+//	}
+//
+// The standard streams of cmd that are nil are set to those of the program.
+// If cmd was terminated by a signal, the program exits with the code returned
+// by [ExitCode] for the signal. If cmd cannot be started, the error is printed
+// to stderr, and the program exits with code 1.
+//
+// Init does not return. On platforms where [Reap] has no effect, such as
+// Windows, Init waits for cmd only, and signals are not forwarded, since a
+// child process receives Ctrl+C from the console itself.
+func Init(cmd *exec.Cmd) {
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	ctx := context.Background()
+	var forwarder *Forwarder
+	if len(initSignals) > 0 {
+		forwarder = Forward(ctx, initSignals...)
+	}
+	// Reap before starting cmd, so that its exit is not missed, and deliver
+	// the exit of cmd once its process ID is known.
+	exits := make(chan ChildExit, 1)
+	started := make(chan int)
+	if canReap {
+		pid := -1
+		Reap(ctx, func(exit ChildExit) {
+			if pid < 0 {
+				pid = <-started
+			}
+			if exit.PID == pid {
+				exits <- exit
+			}
+		})
+	}
+	if err := cmd.Start(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if forwarder != nil {
+		forwarder.Add(cmd.Process)
+	}
+	if !canReap {
+		_ = cmd.Wait()
+		os.Exit(ReadChildExit(cmd.ProcessState).Code())
+	}
+	started <- cmd.Process.Pid
+	os.Exit((<-exits).Code())
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testInitEnv is the shell script that the test binary runs with Init when it
+// is started as a new process by the tests of Init.
+const testInitEnv = "INTERRUPT_TEST_INIT"
+
+func init() {
+	if script := os.Getenv(testInitEnv); script != "" {
+		Init(exec.Command("sh", "-c", script))
+	}
+}
+
+// initCommand returns a command that runs the test binary as an init process
+// for script.
+func initCommand(t *testing.T, script string) *exec.Cmd {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(executable)
+	cmd.Env = append(os.Environ(), testInitEnv+"="+script)
+	cmd.WaitDelay = testTimeout
+	return cmd
+}
+
+// exitCode returns the exit code of a command that exited with the error err
+// from Wait.
+func exitCode(t *testing.T, err error) int {
+	t.Helper()
+	exit, ok := ChildExitFromError(err)
+	if !ok {
+		t.Fatalf("Wait() = %v, want an exit", err)
+	}
+	if exit.Signal != nil {
+		t.Fatalf("Wait() = %v, want an exit code", err)
+	}
+	return exit.ExitCode
+}
+
+func TestInitExitCode(t *testing.T) {
+	err := initCommand(t, "exit 3").Run()
+	if code := exitCode(t, err); code != 3 {
+		t.Errorf("exit code = %d, want 3", code)
+	}
+	if err := initCommand(t, "exit 0").Run(); err != nil {
+		t.Errorf("Run() = %v, want nil", err)
+	}
+}
+
+func TestInitSignalExitCode(t *testing.T) {
+	err := initCommand(t, "kill -KILL $$").Run()
+	if code, want := exitCode(t, err), ExitCode(syscall.SIGKILL); code != want {
+		t.Errorf("exit code = %d, want %d", code, want)
+	}
+}
+
+func TestInitStartFailure(t *testing.T) {
+	cmd := initCommand(t, "exit 0")
+	// sh cannot be found.
+	cmd.Env = append(cmd.Env, "PATH="+t.TempDir())
+	if code := exitCode(t, cmd.Run()); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
+func TestInitForward(t *testing.T) {
+	cmd := initCommand(t, "trap 'exit 7' TERM; echo ready; while :; do sleep 0.01; done")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	timer := time.AfterFunc(testTimeout, func() {
+		_ = cmd.Process.Kill()
+	})
+	defer timer.Stop()
+	if _, err := bufio.NewReader(stdout).ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+	// The signals are forwarded once the script is running, and the init
+	// process exits with the exit code of the script rather than the signal.
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	if code := exitCode(t, cmd.Wait()); code != 7 {
+		t.Errorf("exit code = %d, want 7", code)
+	}
+}
@@ -15,16 +15,49 @@
 // Package interrupt implements handling for interrupt signals.
 //
 // The [Signals] variable extends os.Interrupt with syscall.SIGTERM
-// in unix-like platforms, which should be handled for typical
-// application behavior.
+// in unix-like platforms, Windows, and WebAssembly, which should be handled
+// for typical application behavior.
 //
 // The [Handle] function provides simple [context.Context] propagation
-// of interrupt signals.
+// of interrupt signals, and [HandleWithSignals] does the same for a custom
+// set of signals. The received signal is recorded as the cause of
+// cancellation, and can be inspected with [Received].
+//
+// # Platforms
+//
+// The signals that can be handled, and how they are delivered, differ between
+// platforms:
+//
+//   - On unix-like platforms, [Signals] are os.Interrupt and syscall.SIGTERM,
+//     and [ExtendedSignals] add syscall.SIGHUP and syscall.SIGQUIT.
+//     [ReloadSignals], [UpgradeSignals], and [UserSignals] are syscall.SIGHUP,
+//     syscall.SIGUSR2, and syscall.SIGUSR1 and syscall.SIGUSR2. [ExitCode] is
+//     128 plus the signal number.
+//   - On Windows, [Signals] are os.Interrupt and syscall.SIGTERM. The Go
+//     runtime delivers syscall.SIGTERM when the console window is closed, the
+//     user logs off, or the system shuts down, and keeps the process alive
+//     while the signal is handled, within the limits that Windows allows.
+//     [ExitCode] is as on unix-like platforms.
+//   - On js/wasm, [Signals] are os.Interrupt and syscall.SIGTERM.
+//     syscall.SIGTERM is delivered when a browser unloads the page, and both
+//     signals are delivered when Node.js receives them. WASI preview 1 has no
+//     signals, so on wasip1 they are only delivered by [Trigger]. [ExitCode] is
+//     130 for os.Interrupt and 143 for syscall.SIGTERM.
+//   - On Plan 9, [Signals] are os.Interrupt, which is the "interrupt" note, and
+//     [ExtendedSignals] add the "hangup" note. The "kill" note, like os.Kill
+//     elsewhere, cannot be handled. [ExitCode] is 130 for os.Interrupt.
+//
+// Unless noted, [ExtendedSignals] are the same as [Signals], and
+// [ReloadSignals], [UpgradeSignals], and [UserSignals] are empty. For other
+// signals, [ExitCode] returns 1.
 package interrupt
 
 import (
 	"context"
-	"os/signal"
+	"errors"
+	"os"
+	"slices"
+	"time"
 )
 
 // Handle returns a copy of the parent [context.Context] that is marked done
@@ -33,7 +66,10 @@ import (
 //
 // Signal handling is unregistered automatically by this function when the
 // first interrupt signal arrives, which will restore the default interrupt
-// signal behavior of Go programs (to exit).
+// signal behavior of Go programs (to exit). Signal handling is also
+// unregistered when the parent Context's Done channel is closed. Handle does
+// not start a goroutine per call, so programs may wrap many short-lived
+// contexts without accumulating goroutines.
 //
 // In effect, this function is functionally equivalent to:
 //
@@ -43,6 +79,9 @@ import (
 //	  cancel()
 //	}()
 //
+// except that the received signal is recorded as the cause of cancellation
+// as a [*SignalError], which can be retrieved with [context.Cause] or [Received].
+//
 // Most programs should wrap their contexts using this function to enable interrupt
 // signal handling. The first interrupt signal will result in the context's Done
 // channel closing. The second interrupt signal will result in the program exiting.
@@ -51,11 +90,152 @@ import (
 //	  ctx := interrupt.Handle(context.Background())
 //	  ...
 //	}
-func Handle(ctx context.Context) context.Context {
-	ctx, cancel := signal.NotifyContext(ctx, Signals...)
-	go func() {
-		<-ctx.Done()
-		cancel()
-	}()
+//
+// The behavior of Handle can be adjusted with [Option]s.
+func Handle(ctx context.Context, opts ...Option) context.Context {
+	ctx, _ = HandleWithStop(ctx, opts...)
+	return ctx
+}
+
+// HandleWithSignals is like [Handle], but marks the returned [context.Context]
+// done when any of the given signals arrives instead of [Signals].
+//
+// This is useful for daemons that also want signals such as syscall.SIGHUP or
+// syscall.SIGQUIT to result in the context's Done channel closing:
+//
+//	ctx := interrupt.HandleWithSignals(
+//	  context.Background(),
+//	  append(interrupt.Signals, syscall.SIGHUP, syscall.SIGQUIT)...,
+//	)
+//
+// If no signals are given, [Signals] is used. HandleWithSignals is shorthand
+// for calling [Handle] with [WithSignals].
+func HandleWithSignals(ctx context.Context, signals ...os.Signal) context.Context {
+	return Handle(ctx, WithSignals(signals...))
+}
+
+// HandleSignal is like [Handle], but marks the returned [context.Context] done
+// only when the given signal arrives.
+//
+// This allows flows driven by different signals to be wired independently in
+// the same process, for example a reload flow driven by syscall.SIGHUP and a
+// shutdown flow driven by [Signals]:
+//
+//	reloadCtx := interrupt.HandleSignal(ctx, syscall.SIGHUP)
+//	shutdownCtx := interrupt.Handle(ctx)
+//
+// As with [Handle], signal handling is unregistered when the signal arrives.
+// Unlike with [Handle], the arrival of the signal does not move the program to
+// [StateDraining]. HandleSignal accepts the same [Option]s as [Handle], except
+// that [WithSignals] is ignored.
+func HandleSignal(ctx context.Context, sig os.Signal, opts ...Option) context.Context {
+	ctx, _ = handle(ctx, newOptions(append(slices.Clip(opts), WithSignals(sig))))
 	return ctx
 }
+
+// HandleWithDone is like [Handle], but additionally marks the returned
+// [context.Context] done when any of the given channels is closed.
+//
+// This allows an existing out-of-band "stop now" channel to be merged with
+// interrupt handling:
+//
+//	ctx := interrupt.HandleWithDone(context.Background(), stopC)
+//
+// HandleWithDone is shorthand for calling [Handle] with [WithDone].
+func HandleWithDone(ctx context.Context, done ...<-chan struct{}) context.Context {
+	return Handle(ctx, WithDone(done...))
+}
+
+// HandleWithStop is like [Handle], but additionally returns a stop function.
+//
+// Calling stop unregisters signal handling, releases the resources associated
+// with it, and marks the returned [context.Context] done, in the same manner as
+// the stop function returned by [os/signal.NotifyContext]. This allows interrupt
+// handling to be scoped to part of a program, such as a single subcommand,
+// rather than main:
+//
+//	ctx, stop := interrupt.HandleWithStop(ctx)
+//	defer stop()
+//
+// Calling stop more than once has no effect. Calling stop after a signal has
+// arrived has no effect unless [WithPersistentHandling] is used, in which case
+// signal handling is unregistered.
+func HandleWithStop(ctx context.Context, opts ...Option) (context.Context, context.CancelFunc) {
+	options := newOptions(opts)
+	options.shutdown = true
+	return handle(ctx, options)
+}
+
+// HandleCause is equivalent to [Handle]. It exists to make explicit at the
+// call site that the returned [context.Context] is canceled with a
+// [*SignalError] cause when an interrupt signal arrives.
+//
+// This allows callers to distinguish an interrupt from any other cancellation:
+//
+//	ctx := interrupt.HandleCause(context.Background())
+//	...
+//	var signalError *interrupt.SignalError
+//	if errors.As(context.Cause(ctx), &signalError) {
+//	  // ctx was canceled by signalError.Signal.
+//	}
+//
+// The context's Err method still returns [context.Canceled]. See also [Received].
+func HandleCause(ctx context.Context) context.Context {
+	return HandleWithSignals(ctx, Signals...)
+}
+
+// Wait blocks until an interrupt signal arrives or ctx is done, whichever
+// happens first, and returns the signal that arrived, if any.
+//
+// Wait returns nil if ctx was done before a signal arrived. This is useful for
+// programs that just want to block main until an interrupt:
+//
+//	func main() {
+//	  go serve()
+//	  sig := interrupt.Wait(context.Background())
+//	  ...
+//	}
+//
+// Wait accepts the same [Option]s as [Handle].
+func Wait(ctx context.Context, opts ...Option) os.Signal {
+	ctx = Handle(ctx, opts...)
+	<-ctx.Done()
+	sig, _ := Received(ctx)
+	return sig
+}
+
+// Received returns the signal that caused ctx to be canceled, if any.
+//
+// The second return value is true only if ctx, or one of its parents, was
+// returned by one of the Handle functions and was canceled because a signal
+// arrived. This allows code deep in the call stack to decide how to log or
+// which exit code to use when shutting down:
+//
+//	if sig, ok := interrupt.Received(ctx); ok {
+//	  logger.Info("shutting down", "signal", sig)
+//	}
+func Received(ctx context.Context) (os.Signal, bool) {
+	var signalError *SignalError
+	if errors.As(context.Cause(ctx), &signalError) {
+		return signalError.Signal, true
+	}
+	return nil, false
+}
+
+// ReceivedAt returns the time that the signal that caused ctx to be canceled
+// arrived, if any.
+//
+// The second return value is true under the same conditions as for [Received].
+// This allows shutdown code to accurately compute and log the time spent
+// draining:
+//
+//	if receivedAt, ok := interrupt.ReceivedAt(ctx); ok {
+//	  logger.Info("shutdown complete", "duration", time.Since(receivedAt))
+//	}
+func ReceivedAt(ctx context.Context) (time.Time, bool) {
+	var signalError *SignalError
+	if errors.As(context.Cause(ctx), &signalError) {
+		return signalError.Time, true
+	}
+	return time.Time{}, false
+}
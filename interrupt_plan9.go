@@ -0,0 +1,85 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build plan9
+
+package interrupt
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals are all interrupt signals.
+//
+// On Plan 9, this is os.Interrupt, which is the "interrupt" note.
+var Signals = []os.Signal{os.Interrupt}
+
+// ExtendedSignals are all interrupt signals, along with additional signals that
+// daemons typically treat as a request to stop.
+//
+// On Plan 9, this adds the "hangup" note to [Signals].
+var ExtendedSignals = []os.Signal{os.Interrupt, syscall.Note("hangup")}
+
+// ReloadSignals are the signals that daemons conventionally treat as a request
+// to reload, as used by [Supervise].
+//
+// On Plan 9, there are no such signals.
+var ReloadSignals = []os.Signal{}
+
+// UpgradeSignals are the signals that start an upgrade of an [Upgrader].
+//
+// On Plan 9, there are no such signals.
+var UpgradeSignals = []os.Signal{}
+
+// UserSignals are the signals that are reserved for actions defined by the
+// program, as used by [OnAction].
+//
+// On Plan 9, there are no such signals.
+var UserSignals = []os.Signal{}
+
+// StatusSignals are the signals that conventionally ask a program to report
+// its status, as used by [OnStatus].
+//
+// On Plan 9, there are no such signals.
+var StatusSignals = []os.Signal{}
+
+// terminateSignal is the signal that conventionally asks a program to
+// terminate gracefully.
+var terminateSignal = os.Interrupt
+
+// quitSignals are the signals that conventionally ask for a goroutine dump,
+// as used by DumpOnQuit.
+var quitSignals = []os.Signal{}
+
+// brokenPipeSignals are the signals that indicate a write to a broken pipe,
+// as used by WithBrokenPipe and IgnoreBrokenPipe.
+var brokenPipeSignals = []os.Signal{syscall.Note("sys: write on closed pipe")}
+
+// initSignals are the signals that Init forwards to its child process.
+var initSignals = []os.Signal{}
+
+// canPassFiles is true if open files can be passed to child processes.
+const canPassFiles = false
+
+// ExitCode returns the exit code conventionally used by a program that was
+// terminated by the given signal.
+//
+// On Plan 9, this is 130 for os.Interrupt, and 1 for any other signal.
+func ExitCode(sig os.Signal) int {
+	if sig == os.Interrupt {
+		return 130
+	}
+	return 1
+}
@@ -0,0 +1,254 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testTimeout bounds how long tests wait for something that is expected to
+// happen.
+const testTimeout = 5 * time.Second
+
+// testSignal is a signal that only [Trigger] delivers, so that tests do not
+// register handlers for signals of the operating system.
+type testSignal string
+
+func (s testSignal) String() string {
+	return string(s)
+}
+
+func (testSignal) Signal() {}
+
+const (
+	testInterrupt testSignal = "test interrupt"
+	testReload    testSignal = "test reload"
+)
+
+// resetGlobals resets the global state of the package, and resets it again
+// once t completes.
+func resetGlobals(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		hooksLock.Lock()
+		hooks = nil
+		hooksLock.Unlock()
+		phasesLock.Lock()
+		phases = make(map[Stage]Phase)
+		phasesLock.Unlock()
+		participantsLock.Lock()
+		participants = nil
+		participantsLock.Unlock()
+		childrenLock.Lock()
+		children = nil
+		childrenLock.Unlock()
+		drainersLock.Lock()
+		drainers = nil
+		drainHook = nil
+		drainersLock.Unlock()
+		reloadHooksLock.Lock()
+		reloadHooks = nil
+		reloadHooksLock.Unlock()
+		setShutdownReport(nil)
+		stateLock.Lock()
+		state = StateReady
+		notifiedState = StateReady
+		shutdownSignal = SignalEvent{}
+		stateLock.Unlock()
+		dispatchLock.Lock()
+		stats = Stats{
+			Counts: make(map[os.Signal]int),
+		}
+		dispatchLock.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+// waitSubscriptions waits until n subscriptions are registered for signals,
+// since some are unregistered by [context.AfterFunc] callbacks, and fails t if
+// they are not in time.
+func waitSubscriptions(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for {
+		dispatchLock.Lock()
+		registered := len(subscriptions)
+		dispatchLock.Unlock()
+		if registered == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d subscriptions, want %d", registered, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitDone waits until ctx is done, and fails t if it is not done in time.
+func waitDone(t *testing.T, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(testTimeout):
+		t.Fatal("context not done")
+	}
+}
+
+// assertNotDone fails t if ctx is done, or becomes done shortly.
+func assertNotDone(t *testing.T, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+		t.Fatalf("context done: %v", context.Cause(ctx))
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// logBuffer collects the output of a [*slog.Logger], and is safe for concurrent
+// use.
+type logBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *logBuffer) Write(data []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Write(data)
+}
+
+func (b *logBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.String()
+}
+
+// logger returns a logger that logs to b.
+func (b *logBuffer) logger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(b, nil))
+}
+
+// wait waits until the output contains s, and fails t if it does not in time.
+func (b *logBuffer) wait(t *testing.T, s string) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for !strings.Contains(b.String(), s) {
+		if time.Now().After(deadline) {
+			t.Fatalf("log %q does not contain %q", b.String(), s)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	resetGlobals(t)
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	assertNotDone(t, ctx)
+	before := time.Now()
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want %v", ctx.Err(), context.Canceled)
+	}
+	sig, ok := Received(ctx)
+	if !ok || sig != testInterrupt {
+		t.Errorf("Received() = %v, %v, want %v, true", sig, ok, testInterrupt)
+	}
+	receivedAt, ok := ReceivedAt(ctx)
+	if !ok || receivedAt.Before(before) {
+		t.Errorf("ReceivedAt() = %v, %v, want a time after %v", receivedAt, ok, before)
+	}
+	if !IsInterrupted(context.Cause(ctx)) {
+		t.Errorf("IsInterrupted(%v) = false", context.Cause(ctx))
+	}
+	// Signal handling is unregistered by the first signal.
+	waitSubscriptions(t, 0)
+}
+
+func TestHandleWithStop(t *testing.T) {
+	resetGlobals(t)
+	ctx, stop := HandleWithStop(context.Background(), WithSignals(testInterrupt))
+	stop()
+	waitDone(t, ctx)
+	if _, ok := Received(ctx); ok {
+		t.Error("Received() = true without a signal")
+	}
+	waitSubscriptions(t, 0)
+	stop()
+}
+
+func TestHandleSignalThreshold(t *testing.T) {
+	resetGlobals(t)
+	ctx, stop := HandleWithStop(context.Background(), WithSignals(testInterrupt), WithSignalThreshold(3))
+	defer stop()
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	// Handling remains registered until the signal before the threshold.
+	waitSubscriptions(t, 1)
+	Trigger(testInterrupt)
+	waitSubscriptions(t, 0)
+}
+
+func TestHandleSignal(t *testing.T) {
+	resetGlobals(t)
+	reloadCtx := HandleSignal(context.Background(), testReload)
+	shutdownCtx := Handle(context.Background(), WithSignals(testInterrupt))
+	Trigger(testReload)
+	waitDone(t, reloadCtx)
+	assertNotDone(t, shutdownCtx)
+	if state := ReadState(); state != StateReady {
+		t.Errorf("ReadState() = %v after a reload signal, want %v", state, StateReady)
+	}
+	Trigger(testInterrupt)
+	waitDone(t, shutdownCtx)
+}
+
+func TestHandleDebounce(t *testing.T) {
+	resetGlobals(t)
+	var counts []int
+	options := newOptions([]Option{WithSignals(testInterrupt), WithDebounce(time.Hour), WithPersistentHandling()})
+	options.onSignal = append(options.onSignal, func(_ os.Signal, count int) {
+		counts = append(counts, count)
+	})
+	ctx, stop := handle(context.Background(), options)
+	defer stop()
+	Trigger(testInterrupt)
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	if len(counts) != 1 || counts[0] != 1 {
+		t.Errorf("counts = %v, want [1]", counts)
+	}
+}
+
+func TestHandleParentDone(t *testing.T) {
+	resetGlobals(t)
+	parent, cancel := context.WithCancel(context.Background())
+	ctx := Handle(parent, WithSignals(testInterrupt))
+	cancel()
+	waitDone(t, ctx)
+	if _, ok := Received(ctx); ok {
+		t.Error("Received() = true without a signal")
+	}
+	waitSubscriptions(t, 0)
+}
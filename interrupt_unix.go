@@ -23,6 +23,75 @@ import (
 
 // Signals are all interrupt signals.
 //
-// As opposed to os.Interrupt, this adds syscall.SIGTERM for unix-like platforms. For
-// other platforms, this is just os.Interrupt.
+// On unix-like platforms, this is os.Interrupt and syscall.SIGTERM.
 var Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ExtendedSignals are all interrupt signals, along with additional signals that
+// daemons typically treat as a request to stop.
+//
+// On unix-like platforms, this adds syscall.SIGHUP and syscall.SIGQUIT to
+// [Signals]. Note that handling syscall.SIGQUIT replaces the default behavior
+// of Go programs of exiting with a goroutine dump.
+var ExtendedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT}
+
+// ReloadSignals are the signals that daemons conventionally treat as a request
+// to reload, as used by [Supervise].
+//
+// On unix-like platforms, this is syscall.SIGHUP.
+var ReloadSignals = []os.Signal{syscall.SIGHUP}
+
+// UpgradeSignals are the signals that start an upgrade of an [Upgrader].
+//
+// On unix-like platforms, this is syscall.SIGUSR2.
+var UpgradeSignals = []os.Signal{syscall.SIGUSR2}
+
+// UserSignals are the signals that are reserved for actions defined by the
+// program, as used by [OnAction].
+//
+// On unix-like platforms, this is syscall.SIGUSR1 and syscall.SIGUSR2.
+var UserSignals = []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2}
+
+// StatusSignals are the signals that conventionally ask a program to report
+// its status, as used by [OnStatus].
+//
+// On BSD-derived platforms such as macOS and FreeBSD, this is syscall.SIGINFO.
+// On other unix-like platforms, there are no such signals.
+var StatusSignals = infoSignals
+
+// terminateSignal is the signal that conventionally asks a program to
+// terminate gracefully.
+var terminateSignal os.Signal = syscall.SIGTERM
+
+// quitSignals are the signals that conventionally ask for a goroutine dump,
+// as used by DumpOnQuit.
+var quitSignals = []os.Signal{syscall.SIGQUIT}
+
+// brokenPipeSignals are the signals that indicate a write to a broken pipe,
+// as used by WithBrokenPipe and IgnoreBrokenPipe.
+var brokenPipeSignals = []os.Signal{syscall.SIGPIPE}
+
+// initSignals are the signals that Init forwards to its child process.
+var initSignals = []os.Signal{
+	syscall.SIGHUP,
+	syscall.SIGINT,
+	syscall.SIGQUIT,
+	syscall.SIGTERM,
+	syscall.SIGUSR1,
+	syscall.SIGUSR2,
+	syscall.SIGWINCH,
+}
+
+// canPassFiles is true if open files can be passed to child processes.
+const canPassFiles = true
+
+// ExitCode returns the exit code conventionally used by a program that was
+// terminated by the given signal.
+//
+// On unix-like platforms, this is 128 plus the signal number, for example 130
+// for SIGINT and 143 for SIGTERM.
+func ExitCode(sig os.Signal) int {
+	if sig, ok := sig.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return 1
+}
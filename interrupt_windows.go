@@ -0,0 +1,85 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package interrupt
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals are all interrupt signals.
+//
+// On Windows, this is os.Interrupt and syscall.SIGTERM.
+var Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ExtendedSignals are all interrupt signals, along with additional signals that
+// daemons typically treat as a request to stop.
+//
+// On Windows, this is the same as [Signals].
+var ExtendedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ReloadSignals are the signals that daemons conventionally treat as a request
+// to reload, as used by [Supervise].
+//
+// On Windows, there are no such signals.
+var ReloadSignals = []os.Signal{}
+
+// UpgradeSignals are the signals that start an upgrade of an [Upgrader].
+//
+// On Windows, there are no such signals.
+var UpgradeSignals = []os.Signal{}
+
+// UserSignals are the signals that are reserved for actions defined by the
+// program, as used by [OnAction].
+//
+// On Windows, there are no such signals.
+var UserSignals = []os.Signal{}
+
+// StatusSignals are the signals that conventionally ask a program to report
+// its status, as used by [OnStatus].
+//
+// On Windows, there are no such signals.
+var StatusSignals = []os.Signal{}
+
+// terminateSignal is the signal that conventionally asks a program to
+// terminate gracefully.
+var terminateSignal os.Signal = syscall.SIGTERM
+
+// quitSignals are the signals that conventionally ask for a goroutine dump,
+// as used by DumpOnQuit.
+var quitSignals = []os.Signal{}
+
+// brokenPipeSignals are the signals that indicate a write to a broken pipe,
+// as used by WithBrokenPipe and IgnoreBrokenPipe.
+var brokenPipeSignals = []os.Signal{}
+
+// initSignals are the signals that Init forwards to its child process.
+var initSignals = []os.Signal{}
+
+// canPassFiles is true if open files can be passed to child processes.
+const canPassFiles = false
+
+// ExitCode returns the exit code conventionally used by a program that was
+// terminated by the given signal.
+//
+// On Windows, this is 128 plus the signal number, as on unix-like platforms.
+func ExitCode(sig os.Signal) int {
+	if sig, ok := sig.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return 1
+}
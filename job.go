@@ -0,0 +1,81 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// Job is a group of child processes that are killed together when the
+// program shuts down, so that an interrupted program does not orphan its child
+// processes.
+//
+// On Windows, a Job is a Job Object with JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+// so the child processes are also killed if the program exits abruptly, as the
+// operating system closes the handle of the Job Object. Note that processes
+// started by a child process before it was added to the Job are not part of
+// it. Jobs are not supported on other platforms.
+//
+// A Job is safe for concurrent use.
+type Job struct {
+	job   *job
+	close func() error
+}
+
+// NewJob returns a new [Job], whose child processes are killed by [Job.Close],
+// which is registered as a hook with [OnShutdown] in [StageClose]:
+//
+//	job, err := interrupt.NewJob()
+//	if err != nil {
+//	  return err
+//	}
+//	cmd := exec.Command("worker")
+//	if err := cmd.Start(); err != nil {
+//	  return err
+//	}
+//	if err := job.Add(cmd.Process); err != nil {
+//	  return err
+//	}
+//
+// On platforms other than Windows, NewJob returns an error matching
+// [errors.ErrUnsupported].
+func NewJob() (*Job, error) {
+	job, err := newJob()
+	if err != nil {
+		return nil, err
+	}
+	j := &Job{
+		job:   job,
+		close: sync.OnceValue(job.close),
+	}
+	OnShutdown(func(context.Context) error {
+		return j.Close()
+	}, WithStage(StageClose), WithName("job"))
+	return j, nil
+}
+
+// Add adds process, which should have been started recently, to the Job.
+func (j *Job) Add(process *os.Process) error {
+	return j.job.add(process)
+}
+
+// Close kills all processes in the Job, and releases its resources.
+//
+// Calling Close more than once has no effect.
+func (j *Job) Close() error {
+	return j.close()
+}
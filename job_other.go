@@ -0,0 +1,37 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package interrupt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+type job struct{}
+
+func newJob() (*job, error) {
+	return nil, fmt.Errorf("job: %w", errors.ErrUnsupported)
+}
+
+func (*job) add(*os.Process) error {
+	return fmt.Errorf("job: %w", errors.ErrUnsupported)
+}
+
+func (*job) close() error {
+	return nil
+}
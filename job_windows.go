@@ -0,0 +1,117 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package interrupt
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// jobObjectExtendedLimitInformationClass is JobObjectExtendedLimitInformation.
+	jobObjectExtendedLimitInformationClass = 9
+	// jobObjectLimitKillOnJobClose is JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE.
+	jobObjectLimitKillOnJobClose = 0x2000
+	// processSetQuota is PROCESS_SET_QUOTA.
+	processSetQuota = 0x0100
+)
+
+var (
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = kernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+)
+
+// jobObjectBasicLimitInformation is JOBOBJECT_BASIC_LIMIT_INFORMATION.
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+// ioCounters is IO_COUNTERS.
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+// jobObjectExtendedLimitInformation is JOBOBJECT_EXTENDED_LIMIT_INFORMATION.
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	// IO_COUNTERS is 8-byte aligned on all architectures, as opposed to uint64
+	// fields in Go on 32-bit architectures.
+	_                     [(8 - unsafe.Sizeof(jobObjectBasicLimitInformation{})%8) % 8]byte
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+type job struct {
+	handle syscall.Handle
+}
+
+func newJob() (*job, error) {
+	handle, _, err := procCreateJobObjectW.Call(0, 0)
+	if handle == 0 {
+		return nil, os.NewSyscallError("CreateJobObjectW", err)
+	}
+	info := jobObjectExtendedLimitInformation{
+		BasicLimitInformation: jobObjectBasicLimitInformation{
+			LimitFlags: jobObjectLimitKillOnJobClose,
+		},
+	}
+	if ok, _, err := procSetInformationJobObject.Call(
+		handle,
+		jobObjectExtendedLimitInformationClass,
+		uintptr(unsafe.Pointer(&info)),
+		unsafe.Sizeof(info),
+	); ok == 0 {
+		_ = syscall.CloseHandle(syscall.Handle(handle))
+		return nil, os.NewSyscallError("SetInformationJobObject", err)
+	}
+	return &job{handle: syscall.Handle(handle)}, nil
+}
+
+func (j *job) add(process *os.Process) error {
+	processHandle, err := syscall.OpenProcess(processSetQuota|syscall.PROCESS_TERMINATE, false, uint32(process.Pid))
+	if err != nil {
+		return os.NewSyscallError("OpenProcess", err)
+	}
+	defer syscall.CloseHandle(processHandle)
+	if ok, _, err := procAssignProcessToJobObject.Call(uintptr(j.handle), uintptr(processHandle)); ok == 0 {
+		return os.NewSyscallError("AssignProcessToJobObject", err)
+	}
+	return nil
+}
+
+func (j *job) close() error {
+	return os.NewSyscallError("CloseHandle", syscall.CloseHandle(j.handle))
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// kubernetesGracePeriodEnv is the environment variable from which
+	// KubernetesGracePeriod reads the termination grace period.
+	kubernetesGracePeriodEnv = "TERMINATION_GRACE_PERIOD_SECONDS"
+	// kubernetesDefaultGracePeriod is the default terminationGracePeriodSeconds
+	// of a pod.
+	kubernetesDefaultGracePeriod = 30 * time.Second
+	// kubernetesPreStopDelay is the pre-stop delay of WithKubernetesDefaults,
+	// which is the time that endpoints typically take to be updated.
+	kubernetesPreStopDelay = 5 * time.Second
+	// kubernetesExitMargin is the time between the forced exit of
+	// WithKubernetesDefaults and SIGKILL.
+	kubernetesExitMargin = 2 * time.Second
+)
+
+// KubernetesGracePeriod returns the termination grace period of the pod that
+// the program runs in, which is the time between SIGTERM and SIGKILL.
+//
+// Kubernetes does not expose the terminationGracePeriodSeconds of a pod to its
+// containers, so the period is read from the TERMINATION_GRACE_PERIOD_SECONDS
+// environment variable, which should be set in the pod spec alongside it:
+//
+//	spec:
+//	  terminationGracePeriodSeconds: 60
+//	  containers:
+//	    - env:
+//	        - name: TERMINATION_GRACE_PERIOD_SECONDS
+//	          value: "60"
+//
+// If the variable is not set or invalid, this returns the Kubernetes default
+// of 30 seconds. This allows the hard-stop deadline to be derived from the
+// period instead of hardcoded, leaving a margin to exit cleanly before
+// SIGKILL:
+//
+//	ctx := interrupt.Handle(
+//	  context.Background(),
+//	  interrupt.WithGracePeriod(interrupt.KubernetesGracePeriod()-2*time.Second),
+//	)
+func KubernetesGracePeriod() time.Duration {
+	seconds, err := strconv.ParseUint(os.Getenv(kubernetesGracePeriodEnv), 10, 32)
+	if err != nil {
+		return kubernetesDefaultGracePeriod
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// WithKubernetesDefaults returns a new Option that configures the shutdown
+// choreography that Kubernetes expects of a pod.
+//
+// When SIGTERM arrives, the program moves to [StateDraining], so that
+// [ReadinessHandler] reports the pod as not ready. The returned
+// [context.Context] is marked done after a delay of 5 seconds, or a third of
+// the grace period if shorter, as with [WithPreStopDelay], so that traffic
+// stops being routed to the pod before it stops accepting connections. The
+// program is then forced to exit 2 seconds before the grace period returned by
+// [KubernetesGracePeriod] ends, as with [WithGracePeriod], so that it exits
+// with a meaningful code instead of being killed by SIGKILL:
+//
+//	ctx := interrupt.Handle(context.Background(), interrupt.WithKubernetesDefaults())
+//
+// Options that follow this option override the values it sets.
+func WithKubernetesDefaults() Option {
+	return func(options *options) {
+		gracePeriod := KubernetesGracePeriod()
+		options.preStopDelay = min(kubernetesPreStopDelay, gracePeriod/3)
+		options.gracePeriod = gracePeriod - kubernetesExitMargin
+		if options.gracePeriod <= options.preStopDelay {
+			options.gracePeriod = gracePeriod
+		}
+	}
+}
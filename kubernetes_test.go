@@ -0,0 +1,69 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestKubernetesGracePeriod(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"60":      60 * time.Second,
+		"0":       0,
+		"-1":      30 * time.Second,
+		"invalid": 30 * time.Second,
+		"":        30 * time.Second,
+	} {
+		t.Setenv(kubernetesGracePeriodEnv, value)
+		if gracePeriod := KubernetesGracePeriod(); gracePeriod != want {
+			t.Errorf("KubernetesGracePeriod() = %v with %q, want %v", gracePeriod, value, want)
+		}
+	}
+}
+
+func TestWithKubernetesDefaults(t *testing.T) {
+	for _, testCase := range []struct {
+		value            string
+		wantPreStopDelay time.Duration
+		wantGracePeriod  time.Duration
+	}{
+		{value: "60", wantPreStopDelay: 5 * time.Second, wantGracePeriod: 58 * time.Second},
+		{value: "6", wantPreStopDelay: 2 * time.Second, wantGracePeriod: 4 * time.Second},
+		{value: "3", wantPreStopDelay: time.Second, wantGracePeriod: 3 * time.Second},
+	} {
+		t.Setenv(kubernetesGracePeriodEnv, testCase.value)
+		options := newOptions([]Option{WithKubernetesDefaults()})
+		if options.preStopDelay != testCase.wantPreStopDelay || options.gracePeriod != testCase.wantGracePeriod {
+			t.Errorf(
+				"pre-stop delay %v and grace period %v with %q, want %v and %v",
+				options.preStopDelay, options.gracePeriod, testCase.value, testCase.wantPreStopDelay, testCase.wantGracePeriod,
+			)
+		}
+	}
+}
+
+func TestWithKubernetesDefaultsHandleSignal(t *testing.T) {
+	resetGlobals(t)
+	t.Setenv(kubernetesGracePeriodEnv, "3600")
+	// The option does not make signals that do not start shutdown do so.
+	ctx := HandleSignal(context.Background(), testReload, WithKubernetesDefaults(), WithPreStopDelay(0), WithGracePeriod(0))
+	Trigger(testReload)
+	waitDone(t, ctx)
+	if state := ReadState(); state != StateReady {
+		t.Errorf("ReadState() = %v, want %v", state, StateReady)
+	}
+}
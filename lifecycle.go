@@ -0,0 +1,110 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Lifecycle starts and stops the components of a program in a coordinated,
+// signal-driven way.
+//
+// Components register a function to start them with [Lifecycle.OnStart], and
+// a function to stop them with [Lifecycle.OnStop]. [Lifecycle.Run] calls the
+// start functions in order, waits for an interrupt signal, and then calls the
+// stop functions in reverse order:
+//
+//	var lc interrupt.Lifecycle
+//	lc.OnStart(server.Start)
+//	lc.OnStop(server.Stop)
+//	return lc.Run(ctx)
+//
+// The zero value is ready to use. A Lifecycle is safe for concurrent use, but
+// functions registered after [Lifecycle.Run] is called are not called.
+type Lifecycle struct {
+	lock  sync.Mutex
+	hooks []lifecycleHook
+}
+
+type lifecycleHook struct {
+	fn    func(context.Context) error
+	start bool
+}
+
+// OnStart registers fn to be called by [Lifecycle.Run] to start a component.
+//
+// Start functions should not block for the lifetime of the component, but
+// rather start its work in the background and return.
+func (l *Lifecycle) OnStart(fn func(context.Context) error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.hooks = append(l.hooks, lifecycleHook{fn: fn, start: true})
+}
+
+// OnStop registers fn to be called by [Lifecycle.Run] to stop a component.
+func (l *Lifecycle) OnStop(fn func(context.Context) error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.hooks = append(l.hooks, lifecycleHook{fn: fn})
+}
+
+// Run starts all components, waits until an interrupt signal arrives or ctx is
+// done, and then stops all components.
+//
+// Start functions are called in the order in which they were registered, with
+// a copy of ctx that handles interrupt signals as with [Handle]. If a start
+// function returns an error, no further start functions are called, and only
+// the stop functions registered before the failing start function are called.
+//
+// Stop functions are called in the reverse order in which they were registered,
+// with a context that is not canceled when ctx is done, but retains its values.
+// All stop functions are called, regardless of errors.
+//
+// Run returns the error from the failing start function, if any, joined with
+// the errors from the stop functions. Run accepts the same [Option]s as
+// [Handle].
+func (l *Lifecycle) Run(ctx context.Context, opts ...Option) error {
+	l.lock.Lock()
+	lifecycleHooks := slices.Clone(l.hooks)
+	l.lock.Unlock()
+	ctx, stop := HandleWithStop(ctx, opts...)
+	defer stop()
+	var startErr error
+	started := len(lifecycleHooks)
+	for i, hook := range lifecycleHooks {
+		if !hook.start {
+			continue
+		}
+		if err := hook.fn(ctx); err != nil {
+			startErr = err
+			started = i
+			break
+		}
+	}
+	if startErr == nil {
+		<-ctx.Done()
+	}
+	stopCtx := context.WithoutCancel(ctx)
+	errs := []error{startErr}
+	for i := started - 1; i >= 0; i-- {
+		if !lifecycleHooks[i].start {
+			errs = append(errs, lifecycleHooks[i].fn(stopCtx))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// ErrListenerStopped is the error returned by the Accept method of a
+// [*Listener] once it has stopped accepting connections.
+//
+// It matches [net.ErrClosed], so that accept loops that stop on a closed
+// listener also stop on an interrupt.
+var ErrListenerStopped = fmt.Errorf("listener stopped accepting: %w", net.ErrClosed)
+
+// Listener is a [net.Listener] that stops accepting connections when the
+// program is interrupted, and tracks the connections it accepted that are
+// still open.
+//
+// This gives servers of custom protocols the same graceful behavior as
+// [ServeHTTP]:
+//
+//	listener := interrupt.NewListener(ctx, tcpListener)
+//	for {
+//	  conn, err := listener.Accept()
+//	  if err != nil {
+//	    if errors.Is(err, interrupt.ErrListenerStopped) {
+//	      break
+//	    }
+//	    return err
+//	  }
+//	  go serve(conn)
+//	}
+//	return listener.Drain(drainCtx)
+//
+// A Listener is safe for concurrent use.
+type Listener struct {
+	net.Listener
+	ctx context.Context
+
+	lock      sync.Mutex
+	openConns int
+	// stopped is set once the listener's context is done or the listener is
+	// closed.
+	stopped bool
+	// idleC is closed once the listener stopped and no connections are open.
+	idleC chan struct{}
+	idle  bool
+}
+
+// NewListener returns a new [Listener] that wraps listener, and that stops
+// accepting connections and closes listener once ctx is done.
+func NewListener(ctx context.Context, listener net.Listener) *Listener {
+	l := &Listener{
+		Listener: listener,
+		ctx:      ctx,
+		idleC:    make(chan struct{}),
+	}
+	context.AfterFunc(ctx, func() {
+		_ = l.Close()
+	})
+	return l
+}
+
+// Accept waits for and returns the next connection.
+//
+// Once the listener's context is done or the listener is closed, Accept returns
+// [ErrListenerStopped].
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	// The listener is checked in the same critical section as the connection is
+	// counted, so that the listener cannot become idle in between.
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.stopped || l.ctx.Err() != nil {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return nil, ErrListenerStopped
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.openConns++
+	return &listenerConn{
+		Conn: conn,
+		close: sync.OnceValue(func() error {
+			err := conn.Close()
+			l.lock.Lock()
+			defer l.lock.Unlock()
+			l.openConns--
+			l.closeIdleLocked()
+			return err
+		}),
+	}, nil
+}
+
+// OpenConns returns the number of connections accepted by the listener that
+// have not been closed yet.
+func (l *Listener) OpenConns() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.openConns
+}
+
+// Close closes the listener, so that it stops accepting connections as when its
+// context is done. Connections that it accepted remain open, and can be waited
+// for with [Listener.Drain].
+func (l *Listener) Close() error {
+	err := l.Listener.Close()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.stopped = true
+	l.closeIdleLocked()
+	return err
+}
+
+// Drain waits until the listener has stopped accepting connections and all
+// connections that it accepted have been closed, or until ctx is done.
+//
+// If ctx is done first, Drain returns an error matching the error of ctx.
+func (l *Listener) Drain(ctx context.Context) error {
+	select {
+	case <-l.idleC:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d connections still open: %w", l.OpenConns(), ctx.Err())
+	}
+}
+
+func (l *Listener) closeIdleLocked() {
+	if !l.idle && l.openConns == 0 && l.stopped {
+		l.idle = true
+		close(l.idleC)
+	}
+}
+
+type listenerConn struct {
+	net.Conn
+	close func() error
+}
+
+func (c *listenerConn) Close() error {
+	return c.close()
+}
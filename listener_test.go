@@ -0,0 +1,115 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestListener(t *testing.T) {
+	resetGlobals(t)
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	listener := NewListener(ctx, tcpListener)
+	client, err := net.Dial("tcp", tcpListener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openConns := listener.OpenConns(); openConns != 1 {
+		t.Errorf("OpenConns() = %d, want 1", openConns)
+	}
+	acceptErrC := make(chan error, 1)
+	go func() {
+		_, err := listener.Accept()
+		acceptErrC <- err
+	}()
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	select {
+	case err := <-acceptErrC:
+		if !errors.Is(err, ErrListenerStopped) || !errors.Is(err, net.ErrClosed) {
+			t.Errorf("Accept() = %v after an interrupt, want %v", err, ErrListenerStopped)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("Accept did not return after an interrupt")
+	}
+	drainCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := listener.Drain(drainCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain() = %v with an open connection, want %v", err, context.DeadlineExceeded)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Closing a connection more than once does not count it again.
+	_ = conn.Close()
+	if openConns := listener.OpenConns(); openConns != 0 {
+		t.Errorf("OpenConns() = %d after Close, want 0", openConns)
+	}
+	drainCtx, cancel = context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := listener.Drain(drainCtx); err != nil {
+		t.Errorf("Drain() = %v, want nil", err)
+	}
+}
+
+func TestListenerClose(t *testing.T) {
+	resetGlobals(t)
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := NewListener(context.Background(), tcpListener)
+	client, err := net.Dial("tcp", tcpListener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := listener.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listener.Accept(); !errors.Is(err, ErrListenerStopped) {
+		t.Errorf("Accept() = %v after Close, want %v", err, ErrListenerStopped)
+	}
+	drainCtx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	drainErrC := make(chan error, 1)
+	go func() {
+		drainErrC <- listener.Drain(drainCtx)
+	}()
+	// Closing the listener starts draining, without its context being done.
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-drainErrC; err != nil {
+		t.Errorf("Drain() = %v after Close, want nil", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// LogFile is an [io.Writer] to a log file that is reopened on reload, so that
+// the program writes to a new file once a tool such as logrotate has renamed
+// the previous one.
+//
+// LogFile is created with [OpenLogFile].
+type LogFile struct {
+	path   string
+	lock   sync.Mutex
+	file   *os.File
+	remove func()
+}
+
+// OpenLogFile opens the log file at path for appending, creating it if it does
+// not exist, and returns a [LogFile] that is reopened whenever [Reload] is
+// called, such as by [HandleReload] when syscall.SIGHUP arrives:
+//
+//	logFile, err := interrupt.OpenLogFile("/var/log/daemon.log")
+//	if err != nil {
+//	  return err
+//	}
+//	logger := slog.New(slog.NewJSONHandler(logFile, nil))
+//	interrupt.HandleReload(ctx, interrupt.WithReloadLogger(logger))
+//
+// The file is closed by [Shutdown] after the hooks of [StageClose], so that
+// the other hooks can still log. Writes, reopens, and closes are serialized,
+// so that a reopen cannot race a shutdown, and a log file is never reopened
+// once it has been closed.
+func OpenLogFile(path string) (*LogFile, error) {
+	file, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	logFile := &LogFile{
+		path: path,
+		file: file,
+	}
+	logFile.remove = OnReload(func(context.Context) error {
+		return logFile.Reopen()
+	})
+	OnShutdown(func(context.Context) error {
+		return logFile.Close()
+	}, WithStage(StageClose+1), WithName("log file "+path))
+	return logFile, nil
+}
+
+// Write writes p to the log file.
+func (f *LogFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	return f.file.Write(p)
+}
+
+// Reopen opens the path of the log file again, and closes the previous file.
+//
+// If the path cannot be opened, the log file keeps writing to the previous
+// file. Calling Reopen once the log file is closed has no effect.
+func (f *LogFile) Reopen() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	file, err := openLogFile(f.path)
+	if err != nil {
+		return err
+	}
+	previous := f.file
+	f.file = file
+	return previous.Close()
+}
+
+// Close closes the log file.
+//
+// Calling Close more than once has no effect.
+func (f *LogFile) Close() error {
+	f.remove()
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func openLogFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ToggleLogLevel sets levelVar to level whenever syscall.SIGUSR2 arrives, and
+// restores its previous level when syscall.SIGUSR2 arrives again, or once
+// timeout elapses, until ctx is done.
+//
+// This allows debug logging to be turned on for a live program during an
+// incident, without it being left on by accident:
+//
+//	var levelVar slog.LevelVar
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: &levelVar}))
+//	interrupt.ToggleLogLevel(ctx, &levelVar, slog.LevelDebug, 15*time.Minute)
+//
+// A zero or negative timeout means that the level is only restored by the next
+// syscall.SIGUSR2. The previous level is also restored once ctx is done. The
+// toggle is registered as an action with [OnAction], with the given options.
+// On platforms without syscall.SIGUSR2, such as Windows, ToggleLogLevel has no
+// effect.
+func ToggleLogLevel(ctx context.Context, levelVar *slog.LevelVar, level slog.Level, timeout time.Duration, opts ...ActionOption) {
+	if len(UserSignals) < 2 {
+		return
+	}
+	onTimedAction(ctx, UserSignals[1], "toggle log level", timeout, true, func(context.Context) (func(context.Context) error, error) {
+		previous := levelVar.Level()
+		levelVar.Set(level)
+		return func(context.Context) error {
+			levelVar.Set(previous)
+			return nil
+		}, nil
+	}, opts)
+}
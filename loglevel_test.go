@@ -0,0 +1,102 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+// testToggle is the signal that toggles the log level in the tests of
+// ToggleLogLevel.
+const testToggle testSignal = "test toggle"
+
+func setUserSignals(t *testing.T, signals ...os.Signal) {
+	t.Helper()
+	userSignals := UserSignals
+	UserSignals = signals
+	t.Cleanup(func() {
+		UserSignals = userSignals
+	})
+}
+
+// waitLevel waits until levelVar is set to want, and fails t if it is not in
+// time.
+func waitLevel(t *testing.T, levelVar *slog.LevelVar, want slog.Level) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for levelVar.Level() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("level = %v, want %v", levelVar.Level(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestToggleLogLevel(t *testing.T) {
+	resetGlobals(t)
+	setUserSignals(t, testInterrupt, testToggle)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	var levelVar slog.LevelVar
+	levelVar.Set(slog.LevelWarn)
+	ToggleLogLevel(ctx, &levelVar, slog.LevelDebug, 0, WithActionLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+	Trigger(testToggle)
+	waitLevel(t, &levelVar, slog.LevelDebug)
+	logs.wait(t, `msg="running action" action="toggle log level" signal="test toggle"`)
+	// The next signal restores the previous level.
+	Trigger(testToggle)
+	waitLevel(t, &levelVar, slog.LevelWarn)
+	// So does ctx being done.
+	Trigger(testToggle)
+	waitLevel(t, &levelVar, slog.LevelDebug)
+	cancel()
+	waitLevel(t, &levelVar, slog.LevelWarn)
+}
+
+func TestToggleLogLevelTimeout(t *testing.T) {
+	resetGlobals(t)
+	setUserSignals(t, testInterrupt, testToggle)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	var levelVar slog.LevelVar
+	ToggleLogLevel(ctx, &levelVar, slog.LevelDebug, 20*time.Millisecond, WithActionLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+	Trigger(testToggle)
+	waitLevel(t, &levelVar, slog.LevelDebug)
+	// The previous level is restored once the timeout elapses.
+	waitLevel(t, &levelVar, slog.LevelInfo)
+	// After which the next signal sets the level again.
+	Trigger(testToggle)
+	waitLevel(t, &levelVar, slog.LevelDebug)
+}
+
+func TestToggleLogLevelUnsupported(t *testing.T) {
+	resetGlobals(t)
+	setUserSignals(t)
+	var levelVar slog.LevelVar
+	// The actions of other tests are unregistered asynchronously.
+	before := len(ReadActions())
+	ToggleLogLevel(context.Background(), &levelVar, slog.LevelDebug, 0)
+	if actions := ReadActions(); len(actions) > before {
+		t.Errorf("ReadActions() = %v without user signals, want no new action", actions)
+	}
+}
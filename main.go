@@ -0,0 +1,67 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Main runs fn with [Run], prints any error returned to stderr, and exits the
+// program with a conventional exit code.
+//
+// The program exits with code 0 if fn returned nil, and 1 if fn returned an
+// error. If an interrupt signal arrived and fn returned nil or an error
+// matching [context.Canceled], the program exits with the code conventionally
+// used for termination by that signal, as returned by [ExitCode].
+//
+// Main does not return, and is intended to be the only call in main:
+//
+//	func main() {
+//	  interrupt.Main(run)
+//	}
+//
+//	func run(ctx context.Context) error {
+//	  ...
+//	}
+//
+// Since fn returns before the program exits, deferred calls within fn are run.
+// After fn returns, Main calls [Shutdown] to call the hooks registered with
+// [OnShutdown], and any error from the hooks is treated as an error from fn.
+// Signal handling remains registered while the hooks are called, so that
+// options such as [WithGracePeriod] also bound the time taken by the hooks.
+// Main accepts the same [Option]s as [Handle], and the logger given with
+// [WithLogger] also logs the progress of shutdown, as with [WithReportLogger].
+func Main(fn func(context.Context) error, opts ...Option) {
+	options := newOptions(opts)
+	options.shutdown = true
+	ctx, stop := handle(context.Background(), options)
+	err := runHandled(ctx, fn)
+	if shutdownErr := Shutdown(context.Background(), WithReportLogger(options.logger)); shutdownErr != nil {
+		err = errors.Join(err, shutdownErr)
+	}
+	stop()
+	if err == nil {
+		os.Exit(0)
+	}
+	_, _ = fmt.Fprintln(os.Stderr, err)
+	var signalError *SignalError
+	if errors.As(err, &signalError) {
+		os.Exit(ExitCode(signalError.Signal))
+	}
+	os.Exit(1)
+}
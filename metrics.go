@@ -0,0 +1,91 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"runtime/metrics"
+	"time"
+)
+
+// DumpMetrics writes a snapshot of metrics as JSON whenever sig arrives, until
+// ctx is done, as a cheap record of what the program was doing for postmortems.
+//
+// The snapshot contains the time, the scalar metrics of [runtime/metrics], such
+// as the number of goroutines and the size of the heap, and the value returned
+// by vars, if not nil, such as the variables published with expvar:
+//
+//	interrupt.DumpMetrics(ctx, syscall.SIGUSR1, "/var/tmp/metrics-*.json", func() any {
+//	  vars := make(map[string]json.RawMessage)
+//	  expvar.Do(func(kv expvar.KeyValue) {
+//	    vars[kv.Key] = json.RawMessage(kv.Value.String())
+//	  })
+//	  return vars
+//	})
+//
+// The last "*" in path, if any, is replaced by the current time, as for
+// [DumpHeap]. If path is empty, the snapshot is logged instead. The dump is
+// registered as an action with [OnAction], with the given options, and the path
+// of each snapshot written is logged.
+func DumpMetrics(ctx context.Context, sig os.Signal, path string, vars func() any, opts ...ActionOption) {
+	actionOptions := newActionOptions(opts)
+	OnAction(ctx, sig, "dump metrics", func(ctx context.Context) error {
+		snapshot := readMetricsSnapshot(vars)
+		if path == "" {
+			actionOptions.logger.LogAttrs(ctx, slog.LevelInfo, "metrics", slog.Any("metrics", snapshot))
+			return nil
+		}
+		return actionOptions.writeFile(ctx, "metrics", path, func(file *os.File) error {
+			encoder := json.NewEncoder(file)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(snapshot)
+		})
+	}, opts...)
+}
+
+// metricsSnapshot is a snapshot of metrics written by DumpMetrics.
+type metricsSnapshot struct {
+	Time    time.Time      `json:"time"`
+	Runtime map[string]any `json:"runtime"`
+	Vars    any            `json:"vars,omitempty"`
+}
+
+func readMetricsSnapshot(vars func() any) metricsSnapshot {
+	descriptions := metrics.All()
+	samples := make([]metrics.Sample, len(descriptions))
+	for i, description := range descriptions {
+		samples[i].Name = description.Name
+	}
+	metrics.Read(samples)
+	snapshot := metricsSnapshot{
+		Time:    time.Now(),
+		Runtime: make(map[string]any, len(samples)),
+	}
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			snapshot.Runtime[sample.Name] = sample.Value.Uint64()
+		case metrics.KindFloat64:
+			snapshot.Runtime[sample.Name] = sample.Value.Float64()
+		}
+	}
+	if vars != nil {
+		snapshot.Vars = vars()
+	}
+	return snapshot
+}
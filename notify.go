@@ -0,0 +1,96 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"iter"
+	"os"
+)
+
+// Notify registers for the given signals and returns a channel on which every
+// signal that arrives is delivered, until ctx is done.
+//
+// When ctx is done, signal handling is unregistered with [os/signal.Stop] and the
+// returned channel is closed, so that the channel can be ranged over:
+//
+//	for sig := range interrupt.Notify(ctx) {
+//	  ...
+//	}
+//
+// If no signals are given, [Signals] is used. As with [os/signal.Notify], signals
+// are not delivered if the receiver is not ready and the channel's buffer is
+// full, so receivers should keep up with the channel.
+func Notify(ctx context.Context, signals ...os.Signal) <-chan os.Signal {
+	if len(signals) == 0 {
+		signals = Signals
+	}
+	signalC := make(chan os.Signal, 1)
+	notify(signalC, signals...)
+	context.AfterFunc(ctx, func() {
+		stopNotify(signalC)
+		close(signalC)
+	})
+	return signalC
+}
+
+// Seq returns an iterator over the given signals that arrive, until ctx is done.
+//
+// Signal handling is registered when iteration begins, and unregistered when
+// iteration ends, either because ctx is done or because the loop was exited:
+//
+//	for sig := range interrupt.Seq(ctx) {
+//	  ...
+//	}
+//
+// If no signals are given, [Signals] is used. See [Notify] for details on
+// delivery.
+func Seq(ctx context.Context, signals ...os.Signal) iter.Seq[os.Signal] {
+	return func(yield func(os.Signal) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		for sig := range Notify(ctx, signals...) {
+			if !yield(sig) {
+				return
+			}
+		}
+	}
+}
+
+// OnInterrupt calls f with each interrupt signal in [Signals] that arrives,
+// until ctx is done.
+//
+// This allows programs to act the instant a signal arrives, for example to
+// flush logs or print that shutdown has begun, without managing a channel:
+//
+//	interrupt.OnInterrupt(ctx, func(sig os.Signal) {
+//	  fmt.Fprintln(os.Stderr, "shutting down...")
+//	})
+//
+// Calls to f are made sequentially from a single goroutine. Note that while f is
+// registered, signals are handled by this package, so signals are never
+// delivered with the default behavior of Go programs (to exit).
+func OnInterrupt(ctx context.Context, f func(os.Signal)) {
+	onSignal(ctx, f, Signals)
+}
+
+func onSignal(ctx context.Context, f func(os.Signal), signals []os.Signal) {
+	signalC := Notify(ctx, signals...)
+	go func() {
+		for sig := range signalC {
+			f(sig)
+		}
+	}()
+}
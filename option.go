@@ -0,0 +1,270 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Option is an option for [Handle] and [HandleWithStop].
+//
+// The zero-configuration behavior of [Handle] is appropriate for most
+// programs. Options adjust individual aspects of it without the need to
+// reimplement interrupt handling:
+//
+//	ctx := interrupt.Handle(
+//	  context.Background(),
+//	  interrupt.WithSignals(append(interrupt.Signals, syscall.SIGHUP)...),
+//	  interrupt.WithLogger(logger),
+//	  interrupt.WithGracePeriod(30*time.Second),
+//	)
+type Option func(*options)
+
+// WithSignals returns a new Option that sets the signals that are handled.
+//
+// The default is [Signals]. If no signals are given, [Signals] is used.
+func WithSignals(signals ...os.Signal) Option {
+	return func(options *options) {
+		options.signals = signals
+	}
+}
+
+// WithDone returns a new Option that additionally marks the returned
+// [context.Context] done when any of the given channels is closed.
+//
+// The cause of cancellation is then [context.Canceled]. Multiple uses of this
+// option are cumulative.
+func WithDone(done ...<-chan struct{}) Option {
+	return func(options *options) {
+		options.done = append(options.done, done...)
+	}
+}
+
+// WithLogger returns a new Option that logs signal handling events, such as
+// the arrival of a signal and forced exits, to the given [*slog.Logger].
+//
+// With [Main], the progress of shutdown is also logged, so that the whole
+// lifecycle of the program is visible without additional logging:
+//
+//	interrupt.Main(run, interrupt.WithLogger(slog.Default()))
+//
+// The default is to not log.
+func WithLogger(logger *slog.Logger) Option {
+	return func(options *options) {
+		options.logger = logger
+	}
+}
+
+// WithGracePeriod returns a new Option that forces the program to exit if it
+// is still running the given duration after the first signal arrives.
+//
+// This bounds the time that shutdown can take, regardless of how many
+// signals arrive. The program exits in the same manner as with
+// [WithForceExit] and [WithForceExitCode], using the first signal. The grace
+// period is abandoned if the stop function returned by [HandleWithStop] is
+// called.
+//
+// The default is to wait indefinitely. A zero or negative duration is ignored.
+func WithGracePeriod(gracePeriod time.Duration) Option {
+	return func(options *options) {
+		options.gracePeriod = gracePeriod
+	}
+}
+
+// WithPreStopDelay returns a new Option that delays marking the returned
+// [context.Context] done by the given duration after the first signal arrives.
+//
+// During the delay, the program keeps serving as if no signal had arrived. This
+// gives load balancers and service meshes, which typically take a few seconds
+// to observe that an instance is terminating, time to stop routing new traffic
+// to it before it stops accepting connections. The cause of cancellation is
+// still the [*SignalError] for the first signal, with the time that the signal
+// arrived, and [WithGracePeriod] is measured from the arrival of the signal,
+// so that it bounds the delay as well.
+//
+// The default is no delay. A zero or negative duration is ignored.
+func WithPreStopDelay(delay time.Duration) Option {
+	return func(options *options) {
+		options.preStopDelay = delay
+	}
+}
+
+// WithSignalThreshold returns a new Option that sets the number of signals that
+// must arrive before the program exits.
+//
+// The first signal always results in the context's Done channel closing. By
+// default, the second signal results in the program exiting. With this option,
+// signals before the nth are absorbed, and the nth signal results in the
+// program exiting, either with the default behavior of Go programs or as
+// configured by [WithForceExit] and [WithForceExitCode]. This protects against
+// operators accidentally pressing Ctrl+C twice.
+//
+// A threshold less than 2 is ignored. This option has no effect when used with
+// [WithPersistentHandling] alone, as signals are then never acted on.
+func WithSignalThreshold(n int) Option {
+	return func(options *options) {
+		options.signalThreshold = n
+	}
+}
+
+// WithDebounce returns a new Option that coalesces signals that arrive within
+// the given window after a signal is handled.
+//
+// Some terminals and orchestration layers deliver bursts of signals for a
+// single user action. Without debouncing, the second signal of a burst results
+// in the program exiting immediately. With this option, signals that arrive
+// within the window are discarded, and do not count towards the exit
+// behavior, including [WithSignalThreshold] and [WithForceExit].
+//
+// The default is to not debounce. A zero or negative window is ignored.
+func WithDebounce(window time.Duration) Option {
+	return func(options *options) {
+		options.debounce = window
+	}
+}
+
+// WithForceExit returns a new Option that calls f with the second signal that
+// arrives, or the nth signal if [WithSignalThreshold] is used, after which the
+// program exits with the code from [WithForceExitCode], or [ExitCode] for the
+// signal if not set.
+//
+// By default, signal handling is unregistered when the first signal arrives, so
+// that a second signal exits the program with the default behavior of Go
+// programs, without the program having a chance to react. This option allows
+// programs to print a final message or flush a crash report before the hard
+// exit:
+//
+//	ctx := interrupt.Handle(
+//	  context.Background(),
+//	  interrupt.WithForceExit(func(os.Signal) {
+//	    fmt.Fprintln(os.Stderr, "forcing exit")
+//	  }),
+//	)
+//
+// This option takes precedence over [WithPersistentHandling].
+func WithForceExit(f func(os.Signal)) Option {
+	return func(options *options) {
+		options.onForceExit = f
+	}
+}
+
+// WithForceExitCode returns a new Option that exits the program with the given
+// code when the second signal arrives, or the nth signal if
+// [WithSignalThreshold] is used.
+//
+// See [WithForceExit] for details. This option takes precedence over
+// [WithPersistentHandling].
+func WithForceExitCode(code int) Option {
+	return func(options *options) {
+		options.forceExitCode = &code
+	}
+}
+
+// WithPersistentHandling returns a new Option that keeps signal handling
+// registered after the first signal arrives.
+//
+// By default, signal handling is unregistered when the first signal arrives,
+// so that a second signal results in the program exiting. With this option,
+// subsequent signals are absorbed instead, allowing programs such as TUIs or
+// servers to observe them and continue shutting down on their own terms.
+//
+// Signal handling remains registered until the parent [context.Context] is
+// done or the stop function returned by [HandleWithStop] is called.
+func WithPersistentHandling() Option {
+	return func(options *options) {
+		options.persistent = true
+	}
+}
+
+type options struct {
+	signals     []os.Signal
+	done        []<-chan struct{}
+	logger      *slog.Logger
+	gracePeriod time.Duration
+	// preStopDelay is the time between the first signal and cancellation.
+	preStopDelay time.Duration
+	persistent   bool
+	// brokenPipe is true if SIGPIPE marks the context done.
+	brokenPipe bool
+	debounce   time.Duration
+	// signalThreshold is the number of signals that result in an exit.
+	signalThreshold int
+	// onForceExit is called, if set, before the program is forced to exit.
+	onForceExit func(os.Signal)
+	// forceExitCode is the code to exit with when the program is forced to
+	// exit. If nil, the result of ExitCode is used.
+	forceExitCode *int
+	// onSignal contains functions called with each handled signal, along with
+	// the number of signals received so far.
+	onSignal []func(sig os.Signal, count int)
+	// shutdown is true if the first handled signal starts the shutdown of the
+	// program, as opposed to a flow such as a reload.
+	shutdown bool
+}
+
+func newOptions(opts []Option) *options {
+	options := &options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if len(options.signals) == 0 {
+		options.signals = Signals
+	}
+	if options.signalThreshold < 2 {
+		options.signalThreshold = 2
+	}
+	return options
+}
+
+// hasForceExit returns true if the second signal should force the program to
+// exit, as opposed to being absorbed or resulting in the default behavior.
+func (o *options) hasForceExit() bool {
+	return o.onForceExit != nil || o.forceExitCode != nil
+}
+
+// keepHandling returns true if signal handling should remain registered after
+// count signals have arrived.
+//
+// If false, the next signal results in the default behavior of Go programs.
+func (o *options) keepHandling(count int) bool {
+	return o.persistent || o.hasForceExit() || count < o.signalThreshold-1
+}
+
+func (o *options) signaled(sig os.Signal, count int) {
+	for _, f := range o.onSignal {
+		f(sig, count)
+	}
+}
+
+func (o *options) forceExit(sig os.Signal) {
+	o.log("forcing exit", sig)
+	if o.onForceExit != nil {
+		o.onForceExit(sig)
+	}
+	code := ExitCode(sig)
+	if o.forceExitCode != nil {
+		code = *o.forceExitCode
+	}
+	os.Exit(code)
+}
+
+func (o *options) log(msg string, sig os.Signal) {
+	if o.logger != nil {
+		o.logger.LogAttrs(context.Background(), slog.LevelInfo, msg, slog.String("signal", sig.String()))
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"os"
+	"sync"
+)
+
+var (
+	pauseLock sync.Mutex
+	// pauseC is registered for Signals while paused, so that signals do not
+	// result in the default behavior of Go programs while paused. Signals sent
+	// on pauseC are never delivered, and are discarded.
+	pauseC = make(chan os.Signal, 1)
+)
+
+// Pause pauses all signal handling registered by this package, until [Resume]
+// is called.
+//
+// While paused, signals that arrive are discarded, and do not result in the
+// default behavior of Go programs (to exit). This allows a program to
+// temporarily hand control of the terminal, and the signals it sends, to a
+// child process, such as an editor:
+//
+//	interrupt.Pause()
+//	err := exec.CommandContext(ctx, editor, path).Run()
+//	interrupt.Resume()
+//
+// Calls to Pause may be nested, in which case signal handling resumes when
+// [Resume] has been called once for each call to Pause. See also [Paused].
+func Pause() {
+	pauseLock.Lock()
+	defer pauseLock.Unlock()
+	dispatchLock.Lock()
+	paused++
+	first := paused == 1
+	dispatchLock.Unlock()
+	if first {
+		notify(pauseC, Signals...)
+	}
+}
+
+// Resume resumes signal handling paused by [Pause].
+//
+// Calling Resume when not paused has no effect.
+func Resume() {
+	pauseLock.Lock()
+	defer pauseLock.Unlock()
+	dispatchLock.Lock()
+	if paused == 0 {
+		dispatchLock.Unlock()
+		return
+	}
+	paused--
+	last := paused == 0
+	dispatchLock.Unlock()
+	if last {
+		stopNotify(pauseC)
+	}
+}
+
+// Paused calls fn with signal handling paused, and returns the error from fn.
+//
+// This is a scoped variant of [Pause] and [Resume].
+func Paused(fn func() error) error {
+	Pause()
+	defer Resume()
+	return fn()
+}
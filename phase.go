@@ -0,0 +1,139 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	phasesLock sync.Mutex
+	// phases contains the phases declared with DefinePhase, by stage.
+	phases = make(map[Stage]Phase)
+)
+
+// Phase is a named phase of shutdown, which groups the hooks of a [Stage].
+//
+// This allows a shutdown sequence to be declared up front, with a delay and a
+// timeout for each phase, and hooks to be registered by phase name:
+//
+//	interrupt.DefinePhase(interrupt.Phase{
+//	  Name:  "deregister",
+//	  Stage: interrupt.StageStopAccepting,
+//	  Delay: 5 * time.Second,
+//	})
+//	interrupt.DefinePhase(interrupt.Phase{
+//	  Name:    "drain",
+//	  Stage:   interrupt.StageDrain,
+//	  Timeout: 20 * time.Second,
+//	})
+//	interrupt.DefinePhase(interrupt.Phase{Name: "flush", Stage: interrupt.StageDefault})
+//	interrupt.DefinePhase(interrupt.Phase{Name: "close", Stage: interrupt.StageClose})
+//	...
+//	interrupt.OnShutdown(server.Shutdown, interrupt.WithPhase("drain"))
+type Phase struct {
+	// Name is the name of the phase.
+	Name string
+	// Stage is the stage of the hooks that belong to the phase.
+	Stage Stage
+	// Delay is the time to wait before calling the hooks of the phase, for
+	// example to give load balancers time to stop routing new traffic.
+	Delay time.Duration
+	// Timeout bounds the time that the hooks of the phase may take, as a
+	// whole. Hooks that have not returned by then are abandoned, as with
+	// [WithTimeout]. A zero or negative timeout means no timeout.
+	Timeout time.Duration
+}
+
+// DefinePhase declares a phase of shutdown, replacing any phase previously
+// declared for the same [Stage].
+//
+// [Shutdown] runs every declared phase in the order of its stage, even if no
+// hooks belong to it, so that a phase may consist only of a delay.
+func DefinePhase(phase Phase) {
+	phasesLock.Lock()
+	defer phasesLock.Unlock()
+	phases[phase.Stage] = phase
+}
+
+// WithPhase returns a new HookOption that sets the [Stage] of the hook to the
+// stage of the [Phase] with the given name.
+//
+// The name is resolved when [Shutdown] is called, so phases may be declared
+// after hooks are registered. If no phase with the name is declared by then,
+// the hook is called in [StageDefault].
+func WithPhase(name string) HookOption {
+	return func(hook *hook) {
+		hook.phase = name
+	}
+}
+
+// readPhases returns the declared phases in the order of their stage.
+func readPhases() []Phase {
+	phasesLock.Lock()
+	defer phasesLock.Unlock()
+	sortedPhases := make([]Phase, 0, len(phases))
+	for _, phase := range phases {
+		sortedPhases = append(sortedPhases, phase)
+	}
+	slices.SortFunc(sortedPhases, func(a Phase, b Phase) int {
+		return cmp.Compare(a.Stage, b.Stage)
+	})
+	return sortedPhases
+}
+
+// callPhase waits for the delay of phase, and then calls stageHooks, which are
+// the hooks of phase, within the timeout of phase.
+func callPhase(ctx context.Context, phase Phase, stageHooks []*hook, shutdownOptions *shutdownOptions) []HookReport {
+	start := time.Now()
+	phase.logTransition(shutdownOptions.reportLogger, "shutdown phase started", slog.Int("hooks", len(stageHooks)))
+	if phase.Delay > 0 {
+		timer := time.NewTimer(phase.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+	abandon := false
+	if phase.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, phase.Timeout)
+		defer cancel()
+		abandon = true
+	}
+	hookReports := callHooks(ctx, phase.Name, stageHooks, shutdownOptions, abandon)
+	phase.logTransition(shutdownOptions.reportLogger, "shutdown phase completed", slog.Duration("duration", time.Since(start)))
+	return hookReports
+}
+
+func (p Phase) logTransition(logger *slog.Logger, msg string, attr slog.Attr) {
+	if logger == nil {
+		return
+	}
+	logger.LogAttrs(
+		context.Background(),
+		slog.LevelInfo,
+		msg,
+		slog.String("phase", p.Name),
+		slog.Int("stage", int(p.Stage)),
+		attr,
+	)
+}
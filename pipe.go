@@ -0,0 +1,80 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// ErrBrokenPipe is the cause of cancellation of a [context.Context] returned by
+// one of the Handle functions with [WithBrokenPipe] when syscall.SIGPIPE
+// arrives.
+//
+// It does not match [ErrInterrupted], as a broken pipe is typically not a
+// request to stop, but an indication that output is no longer needed, such as
+// when a pager reading the output of a program exits.
+var ErrBrokenPipe = errors.New("broken pipe")
+
+var (
+	ignoreBrokenPipeOnce sync.Once
+	// brokenPipeC is registered for brokenPipeSignals by IgnoreBrokenPipe.
+	// Signals sent on brokenPipeC are never delivered, and are discarded.
+	brokenPipeC = make(chan os.Signal, 1)
+)
+
+// WithBrokenPipe returns a new Option that additionally marks the returned
+// [context.Context] done when syscall.SIGPIPE arrives, with [ErrBrokenPipe] as
+// the cause.
+//
+// By default, Go programs exit when writing to a broken pipe on standard
+// output or standard error, and return an error matching syscall.EPIPE when
+// writing to a broken pipe on any other file descriptor. With this option,
+// writes to all file descriptors return the error, and the program can stop
+// producing output and exit on its own terms:
+//
+//	ctx := interrupt.Handle(context.Background(), interrupt.WithBrokenPipe())
+//	for _, line := range lines {
+//	  if ctx.Err() != nil {
+//	    break
+//	  }
+//	  fmt.Println(line)
+//	}
+//
+// Note that this includes writes to network connections whose peer has gone
+// away, so this option is intended for command-line programs rather than
+// servers. On Plan 9, this handles the "sys: write on closed pipe" note. On
+// other platforms without syscall.SIGPIPE, such as Windows, this option has no
+// effect.
+func WithBrokenPipe() Option {
+	return func(options *options) {
+		options.brokenPipe = true
+	}
+}
+
+// IgnoreBrokenPipe makes writes to broken pipes return an error matching
+// syscall.EPIPE consistently, for the lifetime of the program, instead of
+// exiting the program when writing to standard output or standard error.
+//
+// On platforms without syscall.SIGPIPE, such as Windows, IgnoreBrokenPipe has
+// no effect. See also [WithBrokenPipe].
+func IgnoreBrokenPipe() {
+	ignoreBrokenPipeOnce.Do(func() {
+		if len(brokenPipeSignals) > 0 {
+			notify(brokenPipeC, brokenPipeSignals...)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WorkerPool supervises a fixed number of worker goroutines that process items
+// from a channel, and drains them when the program is interrupted.
+//
+// Once the context passed to [NewWorkerPool] is done, the pool stops
+// dispatching items, and lets each worker finish its current item up to a drain
+// timeout. Workers that are still busy when the timeout elapses are reported in
+// a [*WorkerDrainError]:
+//
+//	pool := interrupt.NewWorkerPool(ctx, 8, 10*time.Second, process)
+//	if err := pool.Run(queue); err != nil {
+//	  var drainErr *interrupt.WorkerDrainError
+//	  if errors.As(err, &drainErr) {
+//	    logger.Warn("workers did not drain", "workers", drainErr.Workers)
+//	  }
+//	}
+type WorkerPool[T any] struct {
+	ctx     context.Context
+	workers int
+	timeout time.Duration
+	process func(context.Context, T)
+
+	lock sync.Mutex
+	busy []bool
+}
+
+// NewWorkerPool returns a new [WorkerPool] with the given number of workers,
+// each of which calls process for every item it receives.
+//
+// Dispatching stops once ctx is done, which is typically a context returned by
+// one of the Handle functions. process is called with a context that retains
+// the values of ctx, and that is marked done only once the drain timeout has
+// elapsed. If timeout is not positive, the pool waits for busy workers
+// indefinitely. If workers is less than 1, the pool has a single worker.
+func NewWorkerPool[T any](
+	ctx context.Context,
+	workers int,
+	timeout time.Duration,
+	process func(context.Context, T),
+) *WorkerPool[T] {
+	return &WorkerPool[T]{
+		ctx:     ctx,
+		workers: max(workers, 1),
+		timeout: timeout,
+		process: process,
+	}
+}
+
+// Run starts the workers, and blocks until items is closed and drained, or
+// until the pool's context is done and busy workers have finished their
+// current item or the drain timeout has elapsed.
+//
+// Run returns nil if items was closed and all items were processed. Otherwise,
+// it returns the cause of the pool's context, which is a [*SignalError] if the
+// program was interrupted, or a [*WorkerDrainError] if some workers exceeded
+// the drain timeout. Workers that exceeded the drain timeout may still be
+// running when Run returns. Items that remain in items once the pool's context
+// is done are not processed.
+func (p *WorkerPool[T]) Run(items <-chan T) error {
+	processCtx, cancel := context.WithCancelCause(context.WithoutCancel(p.ctx))
+	defer cancel(nil)
+	p.lock.Lock()
+	p.busy = make([]bool, p.workers)
+	p.lock.Unlock()
+	doneC := make(chan struct{})
+	var wg sync.WaitGroup
+	for worker := range p.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(processCtx, worker, items)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(doneC)
+	}()
+	select {
+	case <-doneC:
+		return p.err()
+	case <-p.ctx.Done():
+	}
+	var timeoutC <-chan time.Time
+	if p.timeout > 0 {
+		timer := time.NewTimer(p.timeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+	select {
+	case <-doneC:
+		return p.err()
+	case <-timeoutC:
+		err := &WorkerDrainError{
+			Workers: p.Busy(),
+			Timeout: p.timeout,
+			Cause:   context.Cause(p.ctx),
+		}
+		cancel(err)
+		return err
+	}
+}
+
+// Busy returns the indexes of the workers that are currently processing an
+// item, in increasing order.
+func (p *WorkerPool[T]) Busy() []int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var busy []int
+	for worker, isBusy := range p.busy {
+		if isBusy {
+			busy = append(busy, worker)
+		}
+	}
+	return busy
+}
+
+func (p *WorkerPool[T]) work(ctx context.Context, worker int, items <-chan T) {
+	for p.ctx.Err() == nil {
+		select {
+		case <-p.ctx.Done():
+			return
+		case item, ok := <-items:
+			// Both cases may be ready at once, in which case select picks one
+			// at random.
+			if !ok || p.ctx.Err() != nil {
+				return
+			}
+			p.setBusy(worker, true)
+			p.process(ctx, item)
+			p.setBusy(worker, false)
+		}
+	}
+}
+
+func (p *WorkerPool[T]) setBusy(worker int, busy bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.busy[worker] = busy
+}
+
+func (p *WorkerPool[T]) err() error {
+	if p.ctx.Err() != nil {
+		return context.Cause(p.ctx)
+	}
+	return nil
+}
+
+// WorkerDrainError is the error returned by [WorkerPool.Run] when some workers
+// were still processing an item when the drain timeout elapsed.
+type WorkerDrainError struct {
+	// Workers are the indexes of the workers that exceeded the drain timeout.
+	Workers []int
+	// Timeout is the drain timeout.
+	Timeout time.Duration
+	// Cause is the cause of the pool's context, which is a [*SignalError] if
+	// the program was interrupted.
+	Cause error
+}
+
+// Error implements error.
+func (e *WorkerDrainError) Error() string {
+	return fmt.Sprintf("workers %v did not drain within %v", e.Workers, e.Timeout)
+}
+
+// Unwrap returns the cause of the pool's context, and
+// [context.DeadlineExceeded].
+func (e *WorkerDrainError) Unwrap() []error {
+	return []error{e.Cause, context.DeadlineExceeded}
+}
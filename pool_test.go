@@ -0,0 +1,112 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	resetGlobals(t)
+	var lock sync.Mutex
+	var processed []int
+	pool := NewWorkerPool(context.Background(), 3, 0, func(_ context.Context, item int) {
+		lock.Lock()
+		defer lock.Unlock()
+		processed = append(processed, item)
+	})
+	items := make(chan int, 10)
+	for item := range 10 {
+		items <- item
+	}
+	close(items)
+	if err := pool.Run(items); err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(processed)
+	if want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(processed, want) {
+		t.Errorf("processed %v, want %v", processed, want)
+	}
+}
+
+func TestWorkerPoolInterrupt(t *testing.T) {
+	resetGlobals(t)
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	startedC := make(chan struct{})
+	var processed atomic.Int32
+	pool := NewWorkerPool(ctx, 1, 0, func(ctx context.Context, _ int) {
+		if processed.Add(1) == 1 {
+			close(startedC)
+		}
+		// The current item is finished after an interrupt.
+		<-time.After(20 * time.Millisecond)
+		if ctx.Err() != nil {
+			t.Error("process context done while draining")
+		}
+	})
+	items := make(chan int, 100)
+	for item := range 100 {
+		items <- item
+	}
+	errC := make(chan error, 1)
+	go func() {
+		errC <- pool.Run(items)
+	}()
+	<-startedC
+	Trigger(testInterrupt)
+	err := <-errC
+	if !IsInterrupted(err) {
+		t.Errorf("Run() = %v, want an interrupt", err)
+	}
+	// No item is started once the pool's context is done, even though the
+	// remaining items are ready.
+	if n := processed.Load(); n != 1 {
+		t.Errorf("%d items processed, want 1", n)
+	}
+}
+
+func TestWorkerPoolTimeout(t *testing.T) {
+	resetGlobals(t)
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	startedC := make(chan struct{})
+	pool := NewWorkerPool(ctx, 2, 10*time.Millisecond, func(ctx context.Context, item int) {
+		if item == 0 {
+			close(startedC)
+			<-ctx.Done()
+		}
+	})
+	items := make(chan int, 1)
+	items <- 0
+	errC := make(chan error, 1)
+	go func() {
+		errC <- pool.Run(items)
+	}()
+	<-startedC
+	Trigger(testInterrupt)
+	err := <-errC
+	var drainErr *WorkerDrainError
+	if !errors.As(err, &drainErr) || len(drainErr.Workers) != 1 {
+		t.Fatalf("Run() = %v, want a *WorkerDrainError for 1 worker", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !IsInterrupted(err) {
+		t.Errorf("Run() = %v, want an interrupt and %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import "os"
+
+// SignalGracefully asks process to terminate gracefully, in the manner
+// conventional for the platform.
+//
+// On unix-like platforms, this sends syscall.SIGTERM. On Windows, where
+// signals cannot be sent to other processes, this generates a CTRL_BREAK_EVENT
+// for the process group of process, which the Go runtime of a child process
+// delivers as os.Interrupt. This requires the process to have been started in
+// its own process group, with syscall.CREATE_NEW_PROCESS_GROUP:
+//
+//	cmd := exec.Command("worker")
+//	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+//
+// On other platforms, this sends os.Interrupt. As opposed to [os.Process.Kill],
+// the process has a chance to clean up.
+func SignalGracefully(process *os.Process) error {
+	return signalGracefully(process)
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func signalGracefully(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
+
+func signalProcess(process *os.Process, sig os.Signal) error {
+	return process.Signal(sig)
+}
+
+func signalProcessGroup(pgid int, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf("signal process group: unsupported signal %v", sig)
+	}
+	return syscall.Kill(-pgid, s)
+}
+
+func exitSignal(state *os.ProcessState) os.Signal {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return status.Signal()
+	}
+	return nil
+}
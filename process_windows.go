@@ -0,0 +1,56 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package interrupt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+var procGenerateConsoleCtrlEvent = kernel32.NewProc("GenerateConsoleCtrlEvent")
+
+func signalGracefully(process *os.Process) error {
+	return signalProcessGroup(process.Pid, os.Interrupt)
+}
+
+// signalProcess sends sig to process. Signals other than os.Kill cannot be
+// sent to other processes on Windows, so they are delivered with
+// signalGracefully.
+func signalProcess(process *os.Process, sig os.Signal) error {
+	if sig == os.Kill {
+		return process.Kill()
+	}
+	return signalGracefully(process)
+}
+
+// signalProcessGroup generates a CTRL_BREAK_EVENT for the process group, which
+// the Go runtime delivers as os.Interrupt, for any signal other than os.Kill.
+func signalProcessGroup(pgid int, sig os.Signal) error {
+	if sig == os.Kill {
+		return fmt.Errorf("signal process group: %w", errors.ErrUnsupported)
+	}
+	if ok, _, err := procGenerateConsoleCtrlEvent.Call(syscall.CTRL_BREAK_EVENT, uintptr(pgid)); ok == 0 {
+		return os.NewSyscallError("GenerateConsoleCtrlEvent", err)
+	}
+	return nil
+}
+
+func exitSignal(*os.ProcessState) os.Signal {
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// SetProcessGroup configures cmd to start the program in a new process group,
+// whose ID is the process ID of the program.
+//
+// Terminating only the program of a command leaves the processes it started
+// running, such as the stages of a shell pipeline or the scripts started by a
+// package manager. Once the program is in its own process group, all of them
+// can be signaled at once with [SignalProcessGroup], added to a [Forwarder]
+// with [Forwarder.AddGroup], or stopped with [GroupCommand].
+//
+// On unix-like platforms, this sets Setpgid, and on Windows, this sets
+// syscall.CREATE_NEW_PROCESS_GROUP in the syscall.SysProcAttr of cmd. On other
+// platforms, this has no effect.
+func SetProcessGroup(cmd *exec.Cmd) {
+	setProcessGroup(cmd)
+}
+
+// Detach configures cmd to start the program detached from the terminal of
+// the program, for example to start a daemon in the background before exiting.
+//
+// The terminal does not deliver signals such as the one for Ctrl+C to a
+// detached program, and the program is not stopped when the terminal is
+// closed, while the signals are still delivered to the program as usual:
+//
+//	cmd := exec.Command("daemon")
+//	interrupt.Detach(cmd)
+//	if err := cmd.Start(); err != nil {
+//	  return err
+//	}
+//	return cmd.Process.Release()
+//
+// On unix-like platforms, this starts the program in a new session with
+// Setsid. On Windows, this starts the program without a console, in its own
+// process group. On other platforms, this has no effect.
+func Detach(cmd *exec.Cmd) {
+	detach(cmd)
+}
+
+// SignalProcessGroup sends sig to all processes in the process group with the
+// given ID.
+//
+// On Windows, only a CTRL_BREAK_EVENT can be generated for a process group,
+// which the Go runtime delivers as os.Interrupt, so sig must not be os.Kill.
+// On platforms without process groups, SignalProcessGroup returns an error
+// matching [errors.ErrUnsupported].
+func SignalProcessGroup(pgid int, sig os.Signal) error {
+	return signalProcessGroup(pgid, sig)
+}
+
+// GroupCommand is like [Command], except that the program is started in its
+// own process group with [SetProcessGroup], and all processes in the group are
+// stopped when ctx is done.
+//
+// When ctx is done, all processes in the group are asked to terminate, and
+// those that have not exited after the WaitDelay of the command are killed.
+// The ID of a process group may be reused once all of its processes have
+// exited, so once the program has been waited for, the group is only killed if
+// no process has the ID of the program. On Windows, where process groups
+// cannot be killed, only the program is killed; use a [Job] to kill the
+// processes that it started.
+func GroupCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		process := cmd.Process
+		if cmd.WaitDelay > 0 {
+			time.AfterFunc(cmd.WaitDelay, func() {
+				_ = signalLeaderGroup(process, os.Kill)
+			})
+		}
+		return signalProcessGroup(process.Pid, terminateSignal)
+	}
+	cmd.WaitDelay = commandWaitDelay
+	return cmd
+}
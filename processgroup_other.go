@@ -0,0 +1,35 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !unix && !windows
+
+package interrupt
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(*exec.Cmd) {}
+
+func detach(*exec.Cmd) {}
+
+// startsProcessGroup returns false, since only the program can be stopped.
+func startsProcessGroup(*exec.Cmd) bool {
+	return false
+}
+
+func signalLeaderGroup(process *os.Process, sig os.Signal) error {
+	return signalProcessGroup(process.Pid, sig)
+}
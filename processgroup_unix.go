@@ -0,0 +1,65 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func detach(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// A new session is also a new process group, and setpgid fails for the
+	// leader of a session.
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setpgid = false
+}
+
+// startsProcessGroup returns true if cmd starts the program in a new process
+// group whose ID is the process ID of the program.
+func startsProcessGroup(cmd *exec.Cmd) bool {
+	return cmd.SysProcAttr != nil && cmd.SysProcAttr.Setpgid && cmd.SysProcAttr.Pgid == 0
+}
+
+// signalLeaderGroup sends sig to the process group whose leader is process,
+// and returns [os.ErrProcessDone] if the group has exited.
+//
+// Once process has been waited for, the ID of the group may have been reused
+// by a new group, whose leader then has the same ID. The group is therefore
+// only signaled if no process has the ID of process.
+func signalLeaderGroup(process *os.Process, sig os.Signal) error {
+	if err := process.Signal(syscall.Signal(0)); errors.Is(err, os.ErrProcessDone) {
+		if err := syscall.Kill(process.Pid, 0); !errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+	}
+	if err := signalProcessGroup(process.Pid, sig); !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return os.ErrProcessDone
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startWithGrandchild starts cmd, which must print the process ID of a process
+// that it started on the first line of its output, and returns that ID.
+//
+// The grandchild is killed once t completes, in case the test did not stop it.
+func startWithGrandchild(t *testing.T, cmd *exec.Cmd) int {
+	t.Helper()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = syscall.Kill(pid, syscall.SIGKILL)
+	})
+	return pid
+}
+
+// waitExited waits until the process with the given ID has exited, and fails
+// t if it has not in time.
+//
+// A process that has exited but has not been waited for by its parent yet,
+// such as an orphan adopted by an init process that does not reap, is
+// considered to have exited.
+func waitExited(t *testing.T, pid int) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for !processExited(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("process %d still running", pid)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func processExited(pid int) bool {
+	if err := syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+		return true
+	}
+	// The state of a process is the first field after the parenthesized
+	// command name.
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	_, fields, ok := bytes.Cut(stat, []byte(") "))
+	return ok && bytes.HasPrefix(fields, []byte("Z"))
+}
+
+func TestGroupCommand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := GroupCommand(ctx, "sh", "-c", "sleep 60 >/dev/null & echo $!; wait")
+	grandchild := startWithGrandchild(t, cmd)
+	cancel()
+	if err := cmd.Wait(); err == nil {
+		t.Error("Wait() = nil for a terminated command")
+	}
+	waitExited(t, grandchild)
+}
+
+func TestGroupCommandWaitDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Both processes ignore SIGTERM, so they are killed after the WaitDelay,
+	// including the grandchild once the program has been waited for.
+	cmd := GroupCommand(ctx, "sh", "-c", "trap '' TERM; sleep 60 >/dev/null & echo $!; wait")
+	cmd.WaitDelay = 50 * time.Millisecond
+	grandchild := startWithGrandchild(t, cmd)
+	cancel()
+	_ = cmd.Wait()
+	waitExited(t, grandchild)
+}
+
+func TestSignalProcessGroup(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 60 >/dev/null & echo $!; wait")
+	SetProcessGroup(cmd)
+	grandchild := startWithGrandchild(t, cmd)
+	if err := SignalProcessGroup(cmd.Process.Pid, syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	_ = cmd.Wait()
+	waitExited(t, grandchild)
+}
+
+func TestForwarderAddGroup(t *testing.T) {
+	resetGlobals(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	forwarder := Forward(ctx, syscall.SIGUSR1)
+	cmd := exec.Command("sh", "-c", "sleep 60 >/dev/null & echo $!; wait")
+	SetProcessGroup(cmd)
+	grandchild := startWithGrandchild(t, cmd)
+	remove := forwarder.AddGroup(cmd.Process.Pid)
+	defer remove()
+	waitSubscriptions(t, 1)
+	// The signal is relayed to all processes in the group, which it terminates.
+	Trigger(syscall.SIGUSR1)
+	_ = cmd.Wait()
+	waitExited(t, grandchild)
+}
+
+func TestForwarderAdd(t *testing.T) {
+	resetGlobals(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	forwarder := Forward(ctx, syscall.SIGUSR1)
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	remove := forwarder.Add(cmd.Process)
+	defer remove()
+	waitSubscriptions(t, 1)
+	Trigger(syscall.SIGUSR1)
+	err := cmd.Wait()
+	if exit, ok := ChildExitFromError(err); !ok || exit.Signal != syscall.SIGUSR1 {
+		t.Errorf("Wait() = %v, want termination by %v", err, syscall.SIGUSR1)
+	}
+}
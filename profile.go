@@ -0,0 +1,126 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+)
+
+// DumpHeap writes a heap profile to path whenever syscall.SIGUSR1 arrives,
+// until ctx is done, so that memory profiles can be taken from a program in
+// production without exposing an HTTP endpoint for pprof.
+//
+// The last "*" in path, if any, is replaced by the current time, so that
+// successive profiles are kept:
+//
+//	interrupt.DumpHeap(ctx, "/var/tmp/heap-*.pprof")
+//
+// A garbage collection is run before the profile is written, so that it is
+// up to date. The dump is registered as an action with [OnAction], with the
+// given options, and the path of each profile written is logged. On platforms
+// without syscall.SIGUSR1, such as Windows, DumpHeap has no effect.
+func DumpHeap(ctx context.Context, path string, opts ...ActionOption) {
+	if len(UserSignals) < 1 {
+		return
+	}
+	actionOptions := newActionOptions(opts)
+	OnAction(ctx, UserSignals[0], "dump heap profile", func(ctx context.Context) error {
+		runtime.GC()
+		return actionOptions.writeFile(ctx, "heap profile", path, func(file *os.File) error {
+			return pprof.Lookup("heap").WriteTo(file, 0)
+		})
+	}, opts...)
+}
+
+// ProfileCPU starts a CPU profile written to path when sig arrives, and stops
+// it when sig arrives again, or once duration elapses, until ctx is done:
+//
+//	interrupt.ProfileCPU(ctx, syscall.SIGUSR2, "/var/tmp/cpu-*.pprof", time.Minute)
+//
+// The last "*" in path, if any, is replaced by the current time, as for
+// [DumpHeap]. A profile in progress is stopped and written when ctx is done,
+// and by [Shutdown] after the hooks of [StageClose], so that it is not lost
+// when the program exits. A zero or negative duration means that the profile is
+// only stopped by the next sig.
+//
+// The profile is registered as an action with [OnAction], with the given
+// options, and the path of each profile written is logged. Only one CPU profile
+// can be in progress in a program, so starting a profile fails if another one
+// is in progress, as started with [pprof.StartCPUProfile].
+func ProfileCPU(ctx context.Context, sig os.Signal, path string, duration time.Duration, opts ...ActionOption) {
+	actionOptions := newActionOptions(opts)
+	stop := onTimedAction(ctx, sig, "CPU profile", duration, true, func(context.Context) (func(context.Context) error, error) {
+		return actionOptions.startFile("CPU profile", path, pprof.StartCPUProfile, pprof.StopCPUProfile)
+	}, opts)
+	OnShutdown(stop, WithStage(StageClose+1), WithName("CPU profile"))
+}
+
+// writeFile creates a file at path with createFile, calls write with it, and
+// logs its path under the given description.
+func (a *actionOptions) writeFile(ctx context.Context, description string, path string, write func(*os.File) error) error {
+	file, err := createFile(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return a.closeFile(ctx, description, file)
+}
+
+// startFile creates a file at path with createFile, and calls start with it.
+// The returned function calls stop, and closes the file, logging its path under
+// the given description.
+func (a *actionOptions) startFile(description string, path string, start func(io.Writer) error, stop func()) (func(context.Context) error, error) {
+	file, err := createFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := start(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		stop()
+		return a.closeFile(ctx, description, file)
+	}, nil
+}
+
+// closeFile closes file, and logs its path under the given description.
+func (a *actionOptions) closeFile(ctx context.Context, description string, file *os.File) error {
+	if err := file.Close(); err != nil {
+		return err
+	}
+	a.logger.LogAttrs(ctx, slog.LevelInfo, "wrote "+description, slog.String("path", file.Name()))
+	return nil
+}
+
+// createFile creates the file at path, after replacing the last "*" in path,
+// if any, by the current time.
+func createFile(path string) (*os.File, error) {
+	if i := strings.LastIndex(path, "*"); i >= 0 {
+		path = path[:i] + time.Now().Format("20060102T150405.000") + path[i+1:]
+	}
+	return os.Create(path)
+}
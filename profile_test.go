@@ -0,0 +1,115 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testProfile is the signal that runs actions in the tests of ProfileCPU and
+// CaptureTrace.
+const testProfile testSignal = "test profile"
+
+// waitFiles waits until a file matches pattern, and returns the files that
+// match it, failing t if none does in time.
+func waitFiles(t *testing.T, pattern string) []string {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no file matches %s", pattern)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// assertNotEmpty fails t if one of the given files is empty.
+func assertNotEmpty(t *testing.T, paths []string) {
+	t.Helper()
+	for _, path := range paths {
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fileInfo.Size() == 0 {
+			t.Errorf("%s is empty", path)
+		}
+	}
+}
+
+func TestProfileCPU(t *testing.T) {
+	resetGlobals(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	pattern := filepath.Join(t.TempDir(), "cpu-*.pprof")
+	ProfileCPU(ctx, testProfile, pattern, 0, WithActionLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+	// The first signal starts the profile, and the second one stops it.
+	Trigger(testProfile)
+	waitFiles(t, pattern)
+	Trigger(testProfile)
+	logs.wait(t, `msg="wrote CPU profile" path=`)
+	paths := waitFiles(t, pattern)
+	if len(paths) != 1 {
+		t.Fatalf("profiles = %v, want one", paths)
+	}
+	assertNotEmpty(t, paths)
+}
+
+func TestProfileCPUDuration(t *testing.T) {
+	resetGlobals(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	pattern := filepath.Join(t.TempDir(), "cpu-*.pprof")
+	ProfileCPU(ctx, testProfile, pattern, 20*time.Millisecond, WithActionLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+	Trigger(testProfile)
+	logs.wait(t, `msg="wrote CPU profile" path=`)
+	assertNotEmpty(t, waitFiles(t, pattern))
+}
+
+func TestProfileCPUShutdown(t *testing.T) {
+	resetGlobals(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	pattern := filepath.Join(t.TempDir(), "cpu-*.pprof")
+	ProfileCPU(ctx, testProfile, pattern, 0, WithActionLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+	Trigger(testProfile)
+	// The file is created while the profile is being started, and the profile
+	// is stopped once it has started.
+	waitFiles(t, pattern)
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), `msg="wrote CPU profile"`) {
+		t.Errorf("log %q does not contain the profile written by Shutdown", logs.String())
+	}
+}
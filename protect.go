@@ -0,0 +1,133 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"sync"
+)
+
+// Protect calls fn with a copy of ctx that is not marked done by interrupt
+// signals, and returns the error from fn.
+//
+// This delays the effect of an interrupt on fn until fn returns, so that short
+// critical sections, such as writing a checkpoint or committing a transaction,
+// cannot be torn mid-operation:
+//
+//	err := interrupt.Protect(ctx, func(ctx context.Context) error {
+//	  return tx.Commit(ctx)
+//	})
+//
+// The context passed to fn is still marked done if ctx is done for any reason
+// other than an interrupt signal, such as a parent being canceled or timing
+// out. If ctx was done because of an interrupt signal, the callers of Protect
+// observe this as usual once fn returns. Values of ctx are preserved, but its
+// deadline is not reported by the Deadline method of the context passed to fn.
+func Protect(ctx context.Context, fn func(context.Context) error) error {
+	return fn(Shield(ctx))
+}
+
+// Shield returns a copy of ctx that is not marked done by interrupt signals.
+//
+// The returned context is still marked done if ctx is done for any reason other
+// than an interrupt signal, such as a parent being canceled or timing out. This
+// allows cleanup work to proceed while the program is being asked to stop:
+//
+//	<-ctx.Done()
+//	flush(interrupt.Shield(ctx))
+//
+// Values of ctx are preserved, but its deadline is not reported by the Deadline
+// method of the returned context. See also [Protect].
+func Shield(ctx context.Context) context.Context {
+	return shieldedContext{
+		Context: context.WithoutCancel(ctx),
+		done:    shieldDone(ctx),
+	}
+}
+
+var (
+	shieldsLock sync.Mutex
+	// shields maps the Done channel of each context passed to Shield to the
+	// context returned by shieldDone, so that shielding a long-lived context
+	// repeatedly does not register a new callback with it each time.
+	shields = make(map[<-chan struct{}]context.Context)
+)
+
+// shieldDone returns a context that is marked done once ctx is done for a reason
+// other than an interrupt signal.
+func shieldDone(ctx context.Context) context.Context {
+	done := ctx.Done()
+	if done == nil {
+		return ctx
+	}
+	shieldsLock.Lock()
+	shielded, ok := shields[done]
+	shieldsLock.Unlock()
+	if ok {
+		return shielded
+	}
+	// Once ctx is done because of an interrupt signal, it no longer reflects
+	// the cancellation of its own parents, so the parent of the nearest context
+	// returned by handle is shielded too.
+	upstream := context.Background()
+	if parent, ok := handledParent(ctx); ok {
+		upstream = shieldDone(parent)
+	}
+	shieldsLock.Lock()
+	defer shieldsLock.Unlock()
+	if shielded, ok := shields[done]; ok {
+		return shielded
+	}
+	shielded, cancel := context.WithCancelCause(upstream)
+	stop := context.AfterFunc(ctx, func() {
+		if cause := context.Cause(ctx); !IsInterrupted(cause) {
+			cancel(cause)
+		}
+	})
+	context.AfterFunc(shielded, func() {
+		stop()
+		shieldsLock.Lock()
+		defer shieldsLock.Unlock()
+		delete(shields, done)
+	})
+	shields[done] = shielded
+	return shielded
+}
+
+// shieldedContext is a context with the values of one context, and the
+// cancellation of another.
+type shieldedContext struct {
+	// Context is the context that has the values, and that is never done.
+	context.Context
+
+	done context.Context
+}
+
+func (c shieldedContext) Done() <-chan struct{} {
+	return c.done.Done()
+}
+
+func (c shieldedContext) Err() error {
+	return c.done.Err()
+}
+
+func (c shieldedContext) Value(key any) any {
+	if value := c.Context.Value(key); value != nil {
+		return value
+	}
+	// done only has values of the parents of the context, and the cancellation
+	// used by context.Cause and by the contexts derived from c.
+	return c.done.Value(key)
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProtect(t *testing.T) {
+	resetGlobals(t)
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	err := Protect(ctx, func(ctx context.Context) error {
+		Trigger(testInterrupt)
+		assertNotDone(t, ctx)
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Errorf("Protect() = %v, want nil", err)
+	}
+	waitDone(t, ctx)
+}
+
+func TestShield(t *testing.T) {
+	resetGlobals(t)
+	parentCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := Handle(parentCtx, WithSignals(testInterrupt))
+	shieldedCtx := Shield(ctx)
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	assertNotDone(t, shieldedCtx)
+	// Once interrupted, the shielded context is still done when the parent
+	// of the handled context is canceled.
+	afterCtx := Shield(ctx)
+	assertNotDone(t, afterCtx)
+	cancel()
+	waitDone(t, shieldedCtx)
+	waitDone(t, afterCtx)
+	if err := context.Cause(afterCtx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Cause() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestShieldRepeated(t *testing.T) {
+	resetGlobals(t)
+	before := countShields()
+	ctx, stop := HandleWithStop(context.Background(), WithSignals(testInterrupt))
+	for range 100 {
+		_ = Shield(ctx)
+		_ = Shield(context.WithValue(ctx, testSignal("key"), "value"))
+	}
+	if registered := countShields(); registered != before+1 {
+		t.Errorf("%d shielded contexts, want %d", registered, before+1)
+	}
+	stop()
+	waitDone(t, Shield(ctx))
+	deadline := time.Now().Add(testTimeout)
+	for countShields() != before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d shielded contexts after stop, want %d", countShields(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func countShields() int {
+	shieldsLock.Lock()
+	defer shieldsLock.Unlock()
+	return len(shields)
+}
+
+func TestShieldValues(t *testing.T) {
+	resetGlobals(t)
+	parentCtx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	ctx := context.WithValue(Handle(parentCtx, WithSignals(testInterrupt)), testSignal("key"), "value")
+	shieldedCtx := Shield(ctx)
+	if value := shieldedCtx.Value(testSignal("key")); value != "value" {
+		t.Errorf("Value() = %v, want value", value)
+	}
+	if _, ok := shieldedCtx.Deadline(); ok {
+		t.Error("Deadline() reported for a shielded context")
+	}
+	childCtx, childCancel := context.WithCancel(shieldedCtx)
+	defer childCancel()
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	assertNotDone(t, childCtx)
+	cause := errors.New("canceled")
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	ctx = Handle(cancelCtx, WithSignals(testInterrupt))
+	shieldedCtx = Shield(ctx)
+	cancelCause(cause)
+	waitDone(t, shieldedCtx)
+	if err := context.Cause(shieldedCtx); !errors.Is(err, cause) {
+		t.Errorf("Cause() = %v, want %v", err, cause)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime/pprof"
+	"time"
+)
+
+// DumpOnQuit writes a dump of all goroutines to w whenever syscall.SIGQUIT
+// arrives, until ctx is done.
+//
+// By default, SIGQUIT makes Go programs print a goroutine dump to stderr and
+// exit. Production services often want stack captures without dying, so
+// DumpOnQuit writes the dump, in the same format, to w instead, such as a file
+// or a writer that forwards to a logger. If shutdown is true, DumpOnQuit then
+// requests a graceful shutdown with [RequestShutdown]. Otherwise, the program
+// continues:
+//
+//	file, err := os.Create("/var/log/app/goroutines.txt")
+//	if err != nil {
+//	  return err
+//	}
+//	interrupt.DumpOnQuit(ctx, file, false)
+//
+// Errors writing to w are ignored. On platforms without syscall.SIGQUIT, such
+// as Windows, DumpOnQuit has no effect.
+func DumpOnQuit(ctx context.Context, w io.Writer, shutdown bool) {
+	if len(quitSignals) == 0 {
+		return
+	}
+	onSignal(ctx, func(sig os.Signal) {
+		_, _ = fmt.Fprintf(w, "%s: goroutine dump on %s\n\n", time.Now().Format(time.RFC3339), sig)
+		_ = pprof.Lookup("goroutine").WriteTo(w, 2)
+		if shutdown {
+			RequestShutdown()
+		}
+	}, quitSignals)
+}
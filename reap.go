@@ -0,0 +1,55 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+)
+
+// ChildExit is the exit of a child process reaped by [Reap].
+type ChildExit struct {
+	// PID is the process ID of the child process.
+	PID int
+	// ExitCode is the exit code of the child process, or -1 if it was
+	// terminated by a signal.
+	ExitCode int
+	// Signal is the signal that terminated the child process, if any.
+	Signal os.Signal
+}
+
+// Reap reaps child processes that have terminated whenever syscall.SIGCHLD
+// arrives, until ctx is done, and calls f, if not nil, with each exit.
+//
+// A program that runs as PID 1, typically in a container, adopts all orphaned
+// processes, and must reap them, or they accumulate as zombies:
+//
+//	if os.Getpid() == 1 {
+//	  interrupt.Reap(ctx, func(exit interrupt.ChildExit) {
+//	    logger.Info("reaped", "pid", exit.PID, "code", exit.ExitCode)
+//	  })
+//	}
+//
+// Once ctx is done, Reap reaps the child processes that have terminated in the
+// meantime a final time, and stops. Calls to f are made sequentially from a
+// single goroutine. Note that Reap reaps all child processes, including those
+// started with [os/exec], whose Wait method then fails. On platforms without
+// syscall.SIGCHLD, such as Windows, Reap has no effect.
+func Reap(ctx context.Context, f func(ChildExit)) {
+	if f == nil {
+		f = func(ChildExit) {}
+	}
+	reap(ctx, f)
+}
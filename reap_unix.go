@@ -0,0 +1,59 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix && !aix
+
+package interrupt
+
+import (
+	"context"
+	"syscall"
+)
+
+// canReap is true if Reap reaps child processes.
+const canReap = true
+
+func reap(ctx context.Context, f func(ChildExit)) {
+	signalC := Notify(ctx, syscall.SIGCHLD)
+	go func() {
+		// Reap the child processes that terminated before SIGCHLD was handled.
+		reapAll(f)
+		for range signalC {
+			reapAll(f)
+		}
+		reapAll(f)
+	}()
+}
+
+// reapAll reaps all child processes that have terminated.
+func reapAll(f func(ChildExit)) {
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil || pid <= 0 {
+			return
+		}
+		exit := ChildExit{
+			PID:      pid,
+			ExitCode: status.ExitStatus(),
+		}
+		if status.Signaled() {
+			exit.Signal = status.Signal()
+		}
+		f(exit)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"os"
+	"slices"
+	"sync"
+)
+
+var (
+	recordersLock sync.Mutex
+	// recorders contains the recorders registered with RegisterMetrics, in
+	// registration order.
+	recorders []*registeredRecorder
+)
+
+// MetricsRecorder records metrics about the signals received by this package
+// and about [Shutdown], for export to a metrics system such as Prometheus.
+//
+// This package has no dependencies, so MetricsRecorder is implemented by an
+// adapter to the metrics system, which counts signals by type, tracks whether
+// shutdown is in progress, and observes the drain duration, so that fleets can
+// alert on programs that drain too slowly or receive unexpected signals:
+//
+//	type prometheusRecorder struct {
+//	  signals    *prometheus.CounterVec
+//	  inProgress prometheus.Gauge
+//	  drain      prometheus.Histogram
+//	}
+//
+//	func (r prometheusRecorder) SignalReceived(sig os.Signal) {
+//	  r.signals.WithLabelValues(sig.String()).Inc()
+//	}
+//
+//	func (r prometheusRecorder) ShutdownStarted(interrupt.SignalEvent) {
+//	  r.inProgress.Set(1)
+//	}
+//
+//	func (r prometheusRecorder) ShutdownCompleted(report interrupt.ShutdownReport) {
+//	  r.inProgress.Set(0)
+//	  r.drain.Observe(report.DrainDuration().Seconds())
+//	}
+//
+// Implementations must be safe for concurrent use, and should return quickly,
+// since they are called as signals are delivered.
+type MetricsRecorder interface {
+	// SignalReceived is called when a signal arrives, once per signal, as
+	// counted by [ReadStats].
+	SignalReceived(sig os.Signal)
+	// ShutdownStarted is called when [Shutdown] starts to call the hooks, with
+	// the signal that started the shutdown, as in [ShutdownReport.Signal].
+	ShutdownStarted(signal SignalEvent)
+	// ShutdownCompleted is called with the [ShutdownReport] when [Shutdown]
+	// completes. Its [ShutdownReport.DrainDuration] is measured from the
+	// signal that started the shutdown, not from unrelated signals such as
+	// reload signals.
+	ShutdownCompleted(report ShutdownReport)
+}
+
+type registeredRecorder struct {
+	recorder MetricsRecorder
+}
+
+// RegisterMetrics registers recorder to record metrics about signals and
+// shutdown, and returns a function that unregisters it.
+//
+// Signals received before the recorder is registered are not recorded, so
+// RegisterMetrics should be called early in main.
+func RegisterMetrics(recorder MetricsRecorder) (unregister func()) {
+	registered := &registeredRecorder{
+		recorder: recorder,
+	}
+	recordersLock.Lock()
+	recorders = append(recorders, registered)
+	recordersLock.Unlock()
+	return sync.OnceFunc(func() {
+		recordersLock.Lock()
+		defer recordersLock.Unlock()
+		recorders = slices.DeleteFunc(recorders, func(other *registeredRecorder) bool {
+			return other == registered
+		})
+	})
+}
+
+// record calls f with each registered recorder.
+func record(f func(MetricsRecorder)) {
+	recordersLock.Lock()
+	registeredRecorders := slices.Clone(recorders)
+	recordersLock.Unlock()
+	for _, registered := range registeredRecorders {
+		f(registered.recorder)
+	}
+}
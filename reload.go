@@ -0,0 +1,260 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	reloadHooksLock sync.Mutex
+	// reloadHooks contains the functions registered with OnReload, in
+	// registration order.
+	reloadHooks []*reloadHook
+	// reloadLock serializes calls to Reload.
+	reloadLock sync.Mutex
+)
+
+type reloadHook struct {
+	fn func(context.Context) error
+}
+
+// OnReload registers fn to be called by [Reload], and therefore whenever one
+// of the [ReloadSignals] arrives once [HandleReload] is called, and returns a
+// function that unregisters fn.
+//
+// A function that fails to reload should return an error, and keep using its
+// previous configuration, so that a mistake in a configuration file does not
+// take a running daemon down:
+//
+//	var config atomic.Pointer[Config]
+//	interrupt.OnReload(func(ctx context.Context) error {
+//	  newConfig, err := loadConfig(ctx)
+//	  if err != nil {
+//	    return err
+//	  }
+//	  config.Store(newConfig)
+//	  return nil
+//	})
+func OnReload(fn func(context.Context) error) (remove func()) {
+	registered := &reloadHook{
+		fn: fn,
+	}
+	reloadHooksLock.Lock()
+	reloadHooks = append(reloadHooks, registered)
+	reloadHooksLock.Unlock()
+	return sync.OnceFunc(func() {
+		reloadHooksLock.Lock()
+		defer reloadHooksLock.Unlock()
+		reloadHooks = slices.DeleteFunc(reloadHooks, func(other *reloadHook) bool {
+			return other == registered
+		})
+	})
+}
+
+// Reload calls all functions registered with [OnReload] in the order of their
+// registration, and returns their errors joined with [errors.Join].
+//
+// All functions are called, regardless of errors. Calls to Reload are
+// serialized, so that the functions are never called concurrently with
+// themselves.
+func Reload(ctx context.Context) error {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+	reloadHooksLock.Lock()
+	hooks := slices.Clone(reloadHooks)
+	reloadHooksLock.Unlock()
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook.fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadOption is an option for [HandleReload].
+type ReloadOption func(*reloadOptions)
+
+// WithReloadLogger returns a new ReloadOption that logs reloads, and the
+// errors of failed reloads, to the given [*slog.Logger].
+//
+// The default is to log to [slog.Default].
+func WithReloadLogger(logger *slog.Logger) ReloadOption {
+	return func(reloadOptions *reloadOptions) {
+		reloadOptions.logger = logger
+	}
+}
+
+// WithReloadFile returns a new ReloadOption that also reloads when the file at
+// path changes, as checked every interval.
+//
+// A change is a change of the size or modification time of the file, or the
+// file being replaced by another one, as done by Kubernetes when it updates a
+// mounted ConfigMap by swapping a symbolic link, so that both updates and
+// syscall.SIGHUP reload through the same path:
+//
+//	interrupt.HandleReload(
+//	  ctx,
+//	  interrupt.WithReloadFile("/etc/config/config.yaml", 5*time.Second),
+//	  interrupt.WithReloadDebounce(time.Second),
+//	)
+//
+// The file appearing or disappearing is also a change. An interval of zero or
+// less defaults to one second.
+func WithReloadFile(path string, interval time.Duration) ReloadOption {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return func(reloadOptions *reloadOptions) {
+		reloadOptions.files = append(reloadOptions.files, reloadFile{
+			path:     path,
+			interval: interval,
+		})
+	}
+}
+
+// WithReloadDebounce returns a new ReloadOption that waits until no reload has
+// been triggered for the given duration before reloading, so that a burst of
+// changes, such as several files being updated at once, results in a single
+// reload.
+//
+// The default is to reload as soon as a reload is triggered.
+func WithReloadDebounce(debounce time.Duration) ReloadOption {
+	return func(reloadOptions *reloadOptions) {
+		reloadOptions.debounce = debounce
+	}
+}
+
+type reloadOptions struct {
+	logger   *slog.Logger
+	files    []reloadFile
+	debounce time.Duration
+}
+
+type reloadFile struct {
+	path     string
+	interval time.Duration
+}
+
+// HandleReload calls [Reload] whenever one of the [ReloadSignals] arrives, or
+// one of the files of [WithReloadFile] changes, until ctx is done, with ctx as
+// the context.
+//
+// While ctx is not done, the reload signals no longer terminate the program.
+// Reloads that are triggered while a reload is in progress, by signals or by
+// the files of [WithReloadFile], are coalesced into a single reload once it
+// completes. The errors of failed reloads are logged.
+func HandleReload(ctx context.Context, opts ...ReloadOption) {
+	reloadOptions := &reloadOptions{
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(reloadOptions)
+	}
+	triggerC := make(chan slog.Attr, 1)
+	trigger := func(attr slog.Attr) {
+		select {
+		case triggerC <- attr:
+		default:
+		}
+	}
+	if len(ReloadSignals) > 0 {
+		onSignal(ctx, func(sig os.Signal) {
+			trigger(slog.String("signal", sig.String()))
+		}, ReloadSignals)
+	}
+	for _, file := range reloadOptions.files {
+		go file.watch(ctx, trigger)
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case attr := <-triggerC:
+				if !reloadOptions.wait(ctx, triggerC) {
+					return
+				}
+				reloadOptions.reload(ctx, attr)
+			}
+		}
+	}()
+}
+
+// wait waits until no reload has been triggered on triggerC for the debounce
+// duration, and returns false if ctx is done first.
+func (r *reloadOptions) wait(ctx context.Context, triggerC <-chan slog.Attr) bool {
+	if r.debounce <= 0 {
+		return true
+	}
+	timer := time.NewTimer(r.debounce)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-triggerC:
+			timer.Reset(r.debounce)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// reload calls Reload, and logs the result with the given attributes.
+func (r *reloadOptions) reload(ctx context.Context, attrs ...slog.Attr) {
+	r.logger.LogAttrs(ctx, slog.LevelInfo, "reloading", attrs...)
+	if err := Reload(ctx); err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		r.logger.LogAttrs(ctx, slog.LevelError, "reload failed", attrs...)
+	}
+}
+
+// watch calls trigger whenever the file changes, until ctx is done.
+func (f reloadFile) watch(ctx context.Context, trigger func(slog.Attr)) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	previous, _ := os.Stat(f.path)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current, _ := os.Stat(f.path)
+		if fileChanged(previous, current) {
+			trigger(slog.String("file", f.path))
+		}
+		previous = current
+	}
+}
+
+// fileChanged returns true if the file described by current differs from the
+// file described by previous, either of which is nil if the file did not exist.
+func fileChanged(previous, current os.FileInfo) bool {
+	if previous == nil || current == nil {
+		return previous != current
+	}
+	return !os.SameFile(previous, current) ||
+		previous.Size() != current.Size() ||
+		!previous.ModTime().Equal(current.ModTime())
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReload(t *testing.T) {
+	resetGlobals(t)
+	var called []string
+	errFirst := errors.New("first")
+	errThird := errors.New("third")
+	OnReload(func(context.Context) error {
+		called = append(called, "first")
+		return errFirst
+	})
+	removeSecond := OnReload(func(context.Context) error {
+		called = append(called, "second")
+		return nil
+	})
+	OnReload(func(context.Context) error {
+		called = append(called, "third")
+		return errThird
+	})
+	// All functions are called in order, regardless of errors.
+	err := Reload(context.Background())
+	if !errors.Is(err, errFirst) || !errors.Is(err, errThird) {
+		t.Errorf("Reload() = %v, want errors of first and third", err)
+	}
+	if want := []string{"first", "second", "third"}; !slices.Equal(called, want) {
+		t.Errorf("called = %v, want %v", called, want)
+	}
+	// Removed functions are no longer called, and removing twice is harmless.
+	removeSecond()
+	removeSecond()
+	called = nil
+	_ = Reload(context.Background())
+	if want := []string{"first", "third"}; !slices.Equal(called, want) {
+		t.Errorf("called = %v, want %v", called, want)
+	}
+}
+
+func TestHandleReload(t *testing.T) {
+	resetGlobals(t)
+	setReloadSignals(t, testReload)
+	errReload := errors.New("bad config")
+	results := make(chan error, 1)
+	reloaded := make(chan context.Context, 1)
+	OnReload(func(ctx context.Context) error {
+		reloaded <- ctx
+		return <-results
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	HandleReload(ctx, WithReloadLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+
+	results <- nil
+	Trigger(testReload)
+	select {
+	case got := <-reloaded:
+		if got != ctx {
+			t.Error("reload context is not the context of HandleReload")
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("signal did not reload")
+	}
+	logs.wait(t, `msg=reloading signal="test reload"`)
+
+	// The errors of failed reloads are logged.
+	results <- errReload
+	Trigger(testReload)
+	logs.wait(t, `msg="reload failed" signal="test reload" error="bad config"`)
+	<-reloaded
+
+	// Signals no longer reload once ctx is done.
+	cancel()
+	waitSubscriptions(t, 0)
+	Trigger(testReload)
+	select {
+	case <-reloaded:
+		t.Error("signal reloaded after ctx is done")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHandleReloadFile(t *testing.T) {
+	resetGlobals(t)
+	setReloadSignals(t)
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := make(chan struct{}, 1)
+	OnReload(func(context.Context) error {
+		reloaded <- struct{}{}
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	HandleReload(ctx, WithReloadLogger(logs.logger()), WithReloadFile(path, 5*time.Millisecond))
+	assertNotReloaded(t, reloaded)
+
+	// Replacing the file is a change, as for a Kubernetes ConfigMap.
+	replacement := path + ".new"
+	if err := os.WriteFile(replacement, []byte("b"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(replacement, path); err != nil {
+		t.Fatal(err)
+	}
+	waitReloaded(t, reloaded)
+	logs.wait(t, "msg=reloading file="+path)
+
+	// So is the file disappearing.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	waitReloaded(t, reloaded)
+	assertNotReloaded(t, reloaded)
+}
+
+func TestHandleReloadDebounce(t *testing.T) {
+	resetGlobals(t)
+	setReloadSignals(t, testReload)
+	var reloads atomic.Int32
+	reloaded := make(chan struct{}, 1)
+	OnReload(func(context.Context) error {
+		reloads.Add(1)
+		reloaded <- struct{}{}
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	HandleReload(ctx, WithReloadLogger(logs.logger()), WithReloadDebounce(50*time.Millisecond))
+	waitSubscriptions(t, 1)
+	// A burst of signals results in a single reload.
+	for range 3 {
+		Trigger(testReload)
+		time.Sleep(10 * time.Millisecond)
+	}
+	waitReloaded(t, reloaded)
+	time.Sleep(100 * time.Millisecond)
+	if got := reloads.Load(); got != 1 {
+		t.Errorf("reloads = %d, want 1", got)
+	}
+}
+
+func TestFileChanged(t *testing.T) {
+	dir := t.TempDir()
+	stat := func(name, content string) os.FileInfo {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fileInfo
+	}
+	original := stat("config", "a")
+	same, err := os.Stat(filepath.Join(dir, "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resized := stat("config", "ab")
+	other := stat("other", "ab")
+	for _, testCase := range []struct {
+		name              string
+		previous, current os.FileInfo
+		want              bool
+	}{
+		{name: "missing", want: false},
+		{name: "created", current: original, want: true},
+		{name: "removed", previous: original, want: true},
+		{name: "unchanged", previous: original, current: same, want: false},
+		{name: "resized", previous: original, current: resized, want: true},
+		{name: "replaced", previous: resized, current: other, want: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := fileChanged(testCase.previous, testCase.current); got != testCase.want {
+				t.Errorf("fileChanged() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func waitReloaded(t *testing.T, reloaded <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-reloaded:
+	case <-time.After(testTimeout):
+		t.Fatal("did not reload")
+	}
+}
+
+func assertNotReloaded(t *testing.T, reloaded <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-reloaded:
+		t.Fatal("reloaded without a change")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
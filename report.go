@@ -0,0 +1,165 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	shutdownReportLock sync.Mutex
+	shutdownReport     *ShutdownReport
+)
+
+// ShutdownReport is a report of a call to [Shutdown].
+//
+// This allows grace periods to be tuned with real data about how long each hook
+// and the shutdown as a whole took.
+type ShutdownReport struct {
+	// Signal is the signal that started the shutdown, if any, which is the
+	// first interrupt signal handled by a context returned by one of the Handle
+	// functions other than [HandleSignal]. Other signals, such as reload
+	// signals, are not considered.
+	Signal SignalEvent
+	// Start is the time that the hooks started to be called.
+	Start time.Time
+	// End is the time that all hooks had returned or been abandoned, all
+	// participants had acknowledged or been given up on, and all children had
+	// exited or been killed.
+	End time.Time
+	// Hooks contains a report for each hook, in the order in which the hooks
+	// were called.
+	Hooks []HookReport
+	// Unacknowledged contains the names of the participants registered with
+	// [Participate] that did not acknowledge in time.
+	Unacknowledged []string
+	// Killed contains the names of the children started with [StartChild]
+	// that did not exit in time, and were killed.
+	Killed []string
+}
+
+// HookReport is a report of a call to a hook registered with [OnShutdown].
+type HookReport struct {
+	// Name is the name of the hook, as set by [WithName].
+	Name string
+	// Stage is the stage of the hook.
+	Stage Stage
+	// Phase is the name of the [Phase] declared for the stage of the hook, if
+	// any.
+	Phase string
+	// Start is the time that the hook was called.
+	Start time.Time
+	// Duration is the time that the hook took to return, or to be abandoned.
+	Duration time.Duration
+	// Err is the error returned by the hook, if any.
+	Err error
+}
+
+// ReadShutdownReport returns the report of the most recent call to [Shutdown].
+//
+// The second return value is false if Shutdown has not completed yet.
+func ReadShutdownReport() (ShutdownReport, bool) {
+	shutdownReportLock.Lock()
+	defer shutdownReportLock.Unlock()
+	if shutdownReport == nil {
+		return ShutdownReport{}, false
+	}
+	return *shutdownReport, true
+}
+
+// Duration returns the time that calling the hooks took.
+func (r ShutdownReport) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// DrainDuration returns the time between the arrival of the signal that started
+// the shutdown and the end of the shutdown.
+//
+// If no such signal arrived, this is the same as [ShutdownReport.Duration].
+func (r ShutdownReport) DrainDuration() time.Duration {
+	if r.Signal.Signal == nil {
+		return r.Duration()
+	}
+	return r.End.Sub(r.Signal.Time)
+}
+
+// Err returns the errors of all hooks joined with [errors.Join], along with an
+// error for the participants that did not acknowledge and an error for the
+// children that were killed, if any.
+func (r ShutdownReport) Err() error {
+	errs := make([]error, len(r.Hooks), len(r.Hooks)+2)
+	for i, hookReport := range r.Hooks {
+		errs[i] = hookReport.Err
+	}
+	if len(r.Unacknowledged) > 0 {
+		errs = append(errs, fmt.Errorf("shutdown participants did not acknowledge: %s", strings.Join(r.Unacknowledged, ", ")))
+	}
+	if len(r.Killed) > 0 {
+		errs = append(errs, fmt.Errorf("child processes did not exit and were killed: %s", strings.Join(r.Killed, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+func (r ShutdownReport) logStarted(logger *slog.Logger, hooks int) {
+	attrs := []slog.Attr{
+		slog.Int("hooks", hooks),
+	}
+	if r.Signal.Signal != nil {
+		attrs = append(attrs, slog.String("signal", r.Signal.Signal.String()))
+	}
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "shutdown started", attrs...)
+}
+
+func (r ShutdownReport) log(logger *slog.Logger) {
+	logger.LogAttrs(
+		context.Background(),
+		slog.LevelInfo,
+		"shutdown completed",
+		slog.Int("hooks", len(r.Hooks)),
+		slog.Any("unacknowledged", r.Unacknowledged),
+		slog.Any("killed", r.Killed),
+		slog.Duration("duration", r.Duration()),
+		slog.Duration("drain_duration", r.DrainDuration()),
+	)
+}
+
+func (r HookReport) log(logger *slog.Logger) {
+	attrs := []slog.Attr{
+		slog.String("name", r.Name),
+		slog.Int("stage", int(r.Stage)),
+		slog.Duration("duration", r.Duration),
+	}
+	if r.Phase != "" {
+		attrs = append(attrs, slog.String("phase", r.Phase))
+	}
+	level := slog.LevelInfo
+	if r.Err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", r.Err.Error()))
+	}
+	logger.LogAttrs(context.Background(), level, "shutdown hook completed", attrs...)
+}
+
+func setShutdownReport(report *ShutdownReport) {
+	shutdownReportLock.Lock()
+	defer shutdownReportLock.Unlock()
+	shutdownReport = report
+}
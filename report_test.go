@@ -0,0 +1,79 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdownReport(t *testing.T) {
+	resetGlobals(t)
+	hookErr := errors.New("hook failed")
+	OnShutdown(func(context.Context) error { return nil }, WithName("server"), WithStage(StageStopAccepting))
+	OnShutdown(func(context.Context) error { return hookErr }, WithName("database"), WithStage(StageClose))
+	// A signal that does not start shutdown, such as a reload signal, is not
+	// the signal of the report.
+	reloadCtx := HandleSignal(context.Background(), testReload)
+	Trigger(testReload)
+	waitDone(t, reloadCtx)
+	time.Sleep(20 * time.Millisecond)
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	receivedAt, _ := ReceivedAt(ctx)
+	err := Shutdown(context.Background())
+	report, ok := ReadShutdownReport()
+	if !ok {
+		t.Fatal("ReadShutdownReport() = false after Shutdown")
+	}
+	if report.Signal.Signal != testInterrupt || !report.Signal.Time.Equal(receivedAt) {
+		t.Errorf("report signal %v at %v, want %v at %v", report.Signal.Signal, report.Signal.Time, testInterrupt, receivedAt)
+	}
+	if len(report.Hooks) != 2 {
+		t.Fatalf("%d hook reports, want 2", len(report.Hooks))
+	}
+	if hookReport := report.Hooks[0]; hookReport.Name != "server" || hookReport.Stage != StageStopAccepting || hookReport.Err != nil {
+		t.Errorf("first hook report %+v, want server in stage %v", hookReport, StageStopAccepting)
+	}
+	if hookReport := report.Hooks[1]; hookReport.Name != "database" || hookReport.Stage != StageClose || !errors.Is(hookReport.Err, hookErr) {
+		t.Errorf("second hook report %+v, want database in stage %v with an error", hookReport, StageClose)
+	}
+	if !errors.Is(report.Err(), hookErr) || !errors.Is(err, hookErr) {
+		t.Errorf("Err() = %v, Shutdown() = %v, want %v", report.Err(), err, hookErr)
+	}
+	if report.DrainDuration() < report.Duration() || report.DrainDuration() >= report.Duration()+20*time.Millisecond {
+		t.Errorf("DrainDuration() = %v, want the time since the interrupt, with Duration() = %v", report.DrainDuration(), report.Duration())
+	}
+}
+
+func TestShutdownReportWithoutSignal(t *testing.T) {
+	resetGlobals(t)
+	if _, ok := ReadShutdownReport(); ok {
+		t.Error("ReadShutdownReport() = true before Shutdown")
+	}
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	report, _ := ReadShutdownReport()
+	if report.Signal.Signal != nil {
+		t.Errorf("report signal %v without a signal", report.Signal.Signal)
+	}
+	if report.DrainDuration() != report.Duration() {
+		t.Errorf("DrainDuration() = %v, want Duration() = %v", report.DrainDuration(), report.Duration())
+	}
+}
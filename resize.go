@@ -0,0 +1,61 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+)
+
+// TerminalSize is the size of a terminal, in characters.
+type TerminalSize struct {
+	// Columns is the number of columns.
+	Columns int
+	// Rows is the number of rows.
+	Rows int
+}
+
+// OnResize calls f with the new size of terminal whenever it is resized, as
+// indicated by syscall.SIGWINCH, until ctx is done.
+//
+// This allows TUI programs that use this package for Ctrl+C to also redraw on
+// resize, without a second signal library:
+//
+//	interrupt.OnResize(ctx, os.Stdout, func(size interrupt.TerminalSize) {
+//	  ui.Redraw(size.Columns, size.Rows)
+//	})
+//
+// Calls to f are made sequentially from a single goroutine. Resizes for which
+// the size of terminal cannot be read are skipped. OnResize is supported on
+// Linux, macOS, FreeBSD, NetBSD, and DragonFly BSD, and has no effect on other
+// platforms.
+func OnResize(ctx context.Context, terminal *os.File, f func(TerminalSize)) {
+	if len(resizeSignals) == 0 {
+		return
+	}
+	onSignal(ctx, func(os.Signal) {
+		if size, err := ReadTerminalSize(terminal); err == nil {
+			f(size)
+		}
+	}, resizeSignals)
+}
+
+// ReadTerminalSize returns the size of terminal.
+//
+// On platforms on which [OnResize] is not supported, ReadTerminalSize returns
+// an error matching [errors.ErrUnsupported].
+func ReadTerminalSize(terminal *os.File) (TerminalSize, error) {
+	return readTerminalSize(terminal)
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd
+
+package interrupt
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var resizeSignals = []os.Signal{syscall.SIGWINCH}
+
+// winsize is struct winsize.
+type winsize struct {
+	Row    uint16
+	Col    uint16
+	Xpixel uint16
+	Ypixel uint16
+}
+
+func readTerminalSize(terminal *os.File) (TerminalSize, error) {
+	rawConn, err := terminal.SyscallConn()
+	if err != nil {
+		return TerminalSize{}, err
+	}
+	var ws winsize
+	var errno syscall.Errno
+	if err := rawConn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	}); err != nil {
+		return TerminalSize{}, err
+	}
+	if errno != 0 {
+		return TerminalSize{}, os.NewSyscallError("ioctl", errno)
+	}
+	return TerminalSize{Columns: int(ws.Col), Rows: int(ws.Row)}, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+)
+
+// Run calls fn with a copy of ctx that handles interrupt signals as with
+// [Handle], and returns the error from fn.
+//
+// If an interrupt signal arrived while fn was running, and fn returned nil or
+// an error matching [context.Canceled], Run returns the [*SignalError] for the
+// signal instead, which matches [ErrInterrupted]. Any other error returned by
+// fn is returned as-is. This removes the boilerplate of distinguishing an
+// interrupt from a genuine failure in main:
+//
+//	func main() {
+//	  if err := interrupt.Run(context.Background(), run); err != nil {
+//	    if interrupt.IsInterrupted(err) {
+//	      os.Exit(130)
+//	    }
+//	    ...
+//	  }
+//	}
+//
+// Signal handling is stopped when fn returns. Run accepts the same [Option]s
+// as [Handle].
+func Run(ctx context.Context, fn func(context.Context) error, opts ...Option) error {
+	ctx, stop := HandleWithStop(ctx, opts...)
+	defer stop()
+	return runHandled(ctx, fn)
+}
+
+// runHandled calls fn with ctx, which was returned by one of the Handle
+// functions, and returns the error as documented for [Run].
+func runHandled(ctx context.Context, fn func(context.Context) error) error {
+	return interruptedError(ctx, fn(ctx))
+}
+
+// interruptedError returns the cause of ctx if ctx was canceled by an interrupt
+// signal and err is nil or matches [context.Canceled], and err otherwise.
+func interruptedError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); IsInterrupted(cause) {
+		if err == nil || errors.Is(err, context.Canceled) {
+			return cause
+		}
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+// Runner runs functions in goroutines with a context that is marked done when
+// an interrupt signal arrives, and waits for them to return.
+//
+// This allows main to wait for all goroutines to finish their shutdown before
+// exiting, instead of racing them:
+//
+//	runner := interrupt.NewRunner(context.Background())
+//	runner.Go(serve)
+//	runner.Go(consume)
+//	runner.Wait()
+//
+// A panic in a function called with [Runner.Go] is routed through the same
+// path as an interrupt: the panic is recovered, the Runner's context is
+// canceled with a [*PanicError] as the cause so that the other functions shut
+// down, and [Runner.Wait] calls the hooks registered with [OnShutdown] before
+// panicking again, so that a crash still flushes logs and closes resources.
+//
+// A Runner is safe for concurrent use.
+type Runner struct {
+	ctx    context.Context
+	stop   context.CancelFunc
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	panicOnce sync.Once
+	panicErr  *PanicError
+}
+
+// NewRunner returns a new [Runner] whose functions are called with a copy of
+// ctx that handles interrupt signals as with [Handle].
+//
+// NewRunner accepts the same [Option]s as [Handle].
+func NewRunner(ctx context.Context, opts ...Option) *Runner {
+	ctx, stop := HandleWithStop(ctx, opts...)
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Runner{
+		ctx:    ctx,
+		stop:   stop,
+		cancel: cancel,
+	}
+}
+
+// Go calls fn in a new goroutine with the Runner's context.
+//
+// If fn panics, the panic is recovered, and the Runner's context is canceled
+// with a [*PanicError] as the cause.
+func (r *Runner) Go(fn func(context.Context)) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			if value := recover(); value != nil {
+				r.panicked(&PanicError{Value: value, Stack: debug.Stack()})
+			}
+		}()
+		fn(r.ctx)
+	}()
+}
+
+// Context returns the Runner's context.
+func (r *Runner) Context() context.Context {
+	return r.ctx
+}
+
+// Wait blocks until all functions called with [Runner.Go] have returned, and
+// then unregisters signal handling, so that later interrupt signals result in
+// the default behavior.
+//
+// If any of the functions panicked, Wait then calls [Shutdown], prints any
+// error from the hooks to stderr, and panics with the [*PanicError] for the
+// first panic.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+	r.stop()
+	if r.panicErr != nil {
+		if err := Shutdown(context.Background()); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+		}
+		panic(r.panicErr) //nolint:forbidigo // re-panic after shutdown hooks have run
+	}
+}
+
+func (r *Runner) panicked(panicErr *PanicError) {
+	r.panicOnce.Do(func() {
+		r.panicErr = panicErr
+		r.cancel(panicErr)
+	})
+}
+
+// PanicError is the cause of cancellation of the context of a [Runner] when
+// one of its functions panics.
+type PanicError struct {
+	// Value is the value that the function panicked with.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked, as returned by
+	// [debug.Stack].
+	Stack []byte
+}
+
+// Error implements error.
+//
+// The error includes the stack trace of the goroutine that panicked, so that
+// it is not lost when the panic is propagated.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the value that the function panicked with, if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
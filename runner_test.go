@@ -0,0 +1,62 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunner(t *testing.T) {
+	resetGlobals(t)
+	runner := NewRunner(context.Background(), WithSignals(testInterrupt))
+	runner.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	Trigger(testInterrupt)
+	runner.Wait()
+	if !IsInterrupted(context.Cause(runner.Context())) {
+		t.Errorf("Cause() = %v, want an interrupt", context.Cause(runner.Context()))
+	}
+	waitSubscriptions(t, 0)
+}
+
+func TestRunnerPanic(t *testing.T) {
+	resetGlobals(t)
+	var called bool
+	OnShutdown(func(context.Context) error {
+		called = true
+		return nil
+	})
+	runner := NewRunner(context.Background(), WithSignals(testInterrupt))
+	runner.Go(func(context.Context) {
+		panic("boom") //nolint:forbidigo // tests the recovery of panics
+	})
+	runner.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	defer func() {
+		var panicErr *PanicError
+		if err, _ := recover().(error); !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+			t.Errorf("recovered %v, want a *PanicError for boom", err)
+		}
+		if !called {
+			t.Error("hook not called after a panic")
+		}
+		waitSubscriptions(t, 0)
+	}()
+	runner.Wait()
+}
@@ -0,0 +1,398 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	hooksLock sync.Mutex
+	// hooks contains the hooks registered with OnShutdown, in registration order.
+	hooks []*hook
+)
+
+// Stage is a stage of shutdown, which determines the order in which hooks
+// registered with [OnShutdown] are called.
+//
+// Hooks in lower stages are called before hooks in higher stages. Within a
+// stage, hooks are called in the reverse order of their registration. Stages
+// other than the predefined ones may be used to order hooks between them, for
+// example StageDrain+1.
+type Stage int
+
+const (
+	// StageStopAccepting is the stage for hooks that stop accepting new work,
+	// such as closing listeners or deregistering from service discovery.
+	StageStopAccepting Stage = 100
+	// StageDrain is the stage for hooks that wait for in-flight work to
+	// complete.
+	StageDrain Stage = 200
+	// StageDefault is the stage for hooks registered without [WithStage].
+	StageDefault Stage = 300
+	// StageClose is the stage for hooks that close resources that in-flight
+	// work may depend on, such as storage and clients.
+	StageClose Stage = 400
+)
+
+// HookOption is an option for [OnShutdown].
+type HookOption func(*hook)
+
+// WithStage returns a new HookOption that sets the [Stage] of the hook.
+//
+// This allows independent packages to register hooks and still get a
+// deterministic overall order:
+//
+//	interrupt.OnShutdown(server.Shutdown, interrupt.WithStage(interrupt.StageStopAccepting))
+//	interrupt.OnShutdown(func(context.Context) error {
+//	  return db.Close()
+//	}, interrupt.WithStage(interrupt.StageClose))
+//
+// The default is [StageDefault].
+func WithStage(stage Stage) HookOption {
+	return func(hook *hook) {
+		hook.stage = stage
+	}
+}
+
+// WithTimeout returns a new HookOption that bounds the time that the hook may
+// take.
+//
+// The [context.Context] passed to the hook is canceled when the timeout
+// elapses. If the hook has not returned by then, it is abandoned, so that a
+// single misbehaving hook cannot hang the entire shutdown, and [Shutdown]
+// treats it as having returned an error matching [context.DeadlineExceeded].
+//
+// The default is no timeout. A zero or negative timeout is ignored.
+func WithTimeout(timeout time.Duration) HookOption {
+	return func(hook *hook) {
+		hook.timeout = timeout
+	}
+}
+
+// WithName returns a new HookOption that sets the name of the hook, which is
+// used in the [ShutdownReport].
+func WithName(name string) HookOption {
+	return func(hook *hook) {
+		hook.name = name
+	}
+}
+
+type hook struct {
+	fn      func(context.Context) error
+	name    string
+	stage   Stage
+	phase   string
+	timeout time.Duration
+}
+
+// callReport calls the hook, and returns its report.
+//
+// If abandon is true, the hook is abandoned once ctx is done.
+func (h *hook) callReport(ctx context.Context, abandon bool) HookReport {
+	start := time.Now()
+	err := h.call(ctx, abandon)
+	return HookReport{
+		Name:     h.name,
+		Stage:    h.stage,
+		Start:    start,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+}
+
+func (h *hook) call(ctx context.Context, abandon bool) error {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+		abandon = true
+	}
+	if !abandon {
+		return h.fn(ctx)
+	}
+	errC := make(chan error, 1)
+	go func() {
+		errC <- h.fn(ctx)
+	}()
+	select {
+	case err := <-errC:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("shutdown hook abandoned: %w", ctx.Err())
+	}
+}
+
+// OnShutdown registers fn to be called by [Shutdown].
+//
+// Hooks are called in the order of their [Stage], and within a stage in the
+// reverse order of their registration, so that components registering their
+// cleanup as they are constructed are torn down in the reverse order of their
+// construction:
+//
+//	db := openDB()
+//	interrupt.OnShutdown(func(ctx context.Context) error {
+//	  return db.Close()
+//	})
+//
+// [Main] calls [Shutdown] after its function returns and before the program
+// exits, including when an interrupt signal arrived.
+func OnShutdown(fn func(context.Context) error, opts ...HookOption) {
+	onShutdown(fn, opts...)
+}
+
+// onShutdown is like OnShutdown, and returns the registered hook.
+func onShutdown(fn func(context.Context) error, opts ...HookOption) *hook {
+	hook := &hook{
+		fn:    fn,
+		stage: StageDefault,
+	}
+	for _, opt := range opts {
+		opt(hook)
+	}
+	hooksLock.Lock()
+	defer hooksLock.Unlock()
+	hooks = append(hooks, hook)
+	return hook
+}
+
+// registered returns true if h is registered, as it is until Shutdown is called
+// without WithDryRun.
+func (h *hook) registered() bool {
+	hooksLock.Lock()
+	defer hooksLock.Unlock()
+	return slices.Contains(hooks, h)
+}
+
+// ShutdownOption is an option for [Shutdown].
+type ShutdownOption func(*shutdownOptions)
+
+// WithParallel returns a new ShutdownOption that calls the hooks within each
+// [Stage] concurrently, with at most limit hooks running at once.
+//
+// Stages are still run in order, so hooks that depend on each other should be
+// registered in different stages. This allows teardown of many independent
+// subsystems to fit within a grace period such as the 30 seconds that
+// Kubernetes allows by default.
+//
+// A limit of zero or less means no limit. The default is to call hooks
+// sequentially.
+func WithParallel(limit int) ShutdownOption {
+	return func(shutdownOptions *shutdownOptions) {
+		shutdownOptions.parallel = true
+		shutdownOptions.limit = limit
+	}
+}
+
+// WithReportLogger returns a new ShutdownOption that logs the progress of
+// shutdown to the given [*slog.Logger]: its start, the completion of each hook
+// as it happens, the transitions between the phases declared with
+// [DefinePhase], and the [ShutdownReport] once shutdown completes.
+//
+// Logging each hook as it completes shows which hook a stuck shutdown is
+// waiting on. [Main] uses the logger given with [WithLogger].
+//
+// The default is to not log.
+func WithReportLogger(logger *slog.Logger) ShutdownOption {
+	return func(shutdownOptions *shutdownOptions) {
+		shutdownOptions.reportLogger = logger
+	}
+}
+
+// WithDryRun returns a new ShutdownOption that keeps the hooks registered after
+// they are called.
+//
+// This allows tests to execute the shutdown sequence, without a signal and
+// without exiting, and assert on the [ShutdownReport] that all hooks ran, in
+// order, and within budget:
+//
+//	if err := interrupt.Shutdown(ctx, interrupt.WithDryRun()); err != nil {
+//	  t.Fatal(err)
+//	}
+//	report, _ := interrupt.ReadShutdownReport()
+//	if report.Duration() > 10*time.Second {
+//	  t.Errorf("shutdown took %v", report.Duration())
+//	}
+//
+// Note that the hooks themselves are still called, so they should be safe to
+// call again in a later shutdown.
+func WithDryRun() ShutdownOption {
+	return func(shutdownOptions *shutdownOptions) {
+		shutdownOptions.dryRun = true
+	}
+}
+
+// Shutdown calls all hooks registered with [OnShutdown] in the order of their
+// [Stage], and within a stage in the reverse order of their registration.
+//
+// The stages of the phases declared with [DefinePhase] are run as phases, with
+// their delay and timeout, even if no hooks belong to them.
+//
+// All hooks are called, regardless of errors. The errors returned by all hooks
+// are joined with [errors.Join], in the order in which the hooks were called,
+// so that every failure of an unclean shutdown can be diagnosed.
+//
+// After the hooks are called, Shutdown waits for the participants registered
+// with [Participate] to acknowledge, and returns an error if any did not. It
+// then waits for the children started with [StartChild] to exit, and returns
+// an error if any had to be killed.
+//
+// Each hook is called at most once: hooks are unregistered when Shutdown is
+// called, so subsequent calls only call hooks registered in the meantime,
+// unless [WithDryRun] is used. Once Shutdown completes, a [ShutdownReport] is
+// available from [ReadShutdownReport], and unless WithDryRun is used, the
+// program is in [StateStopped].
+func Shutdown(ctx context.Context, opts ...ShutdownOption) error {
+	shutdownOptions := &shutdownOptions{}
+	for _, opt := range opts {
+		opt(shutdownOptions)
+	}
+	report := &ShutdownReport{
+		Signal: readShutdownSignal(),
+		Start:  time.Now(),
+	}
+	sortedPhases := readPhases()
+	takenHooks := takeHooks(shutdownOptions.dryRun, sortedPhases)
+	if shutdownOptions.reportLogger != nil {
+		report.logStarted(shutdownOptions.reportLogger, len(takenHooks))
+	}
+	record(func(recorder MetricsRecorder) {
+		recorder.ShutdownStarted(report.Signal)
+	})
+	for len(takenHooks) > 0 || len(sortedPhases) > 0 {
+		var stage Stage
+		switch {
+		case len(takenHooks) == 0:
+			stage = sortedPhases[0].Stage
+		case len(sortedPhases) == 0:
+			stage = takenHooks[0].stage
+		default:
+			stage = min(takenHooks[0].stage, sortedPhases[0].Stage)
+		}
+		end := 0
+		for end < len(takenHooks) && takenHooks[end].stage == stage {
+			end++
+		}
+		if len(sortedPhases) > 0 && sortedPhases[0].Stage == stage {
+			report.Hooks = append(report.Hooks, callPhase(ctx, sortedPhases[0], takenHooks[:end], shutdownOptions)...)
+			sortedPhases = sortedPhases[1:]
+		} else {
+			report.Hooks = append(report.Hooks, callHooks(ctx, "", takenHooks[:end], shutdownOptions, false)...)
+		}
+		takenHooks = takenHooks[end:]
+	}
+	report.Unacknowledged = waitParticipants(ctx, shutdownOptions.ackTimeout)
+	report.Killed = waitChildren(ctx, shutdownOptions.ackTimeout)
+	report.End = time.Now()
+	setShutdownReport(report)
+	if !shutdownOptions.dryRun {
+		advanceState(StateStopped)
+	}
+	if shutdownOptions.reportLogger != nil {
+		report.log(shutdownOptions.reportLogger)
+	}
+	record(func(recorder MetricsRecorder) {
+		recorder.ShutdownCompleted(*report)
+	})
+	return report.Err()
+}
+
+type shutdownOptions struct {
+	parallel     bool
+	limit        int
+	reportLogger *slog.Logger
+	dryRun       bool
+	ackTimeout   time.Duration
+}
+
+// callHooks calls the given hooks, which belong to the named phase, if any, and
+// returns their reports in the same order.
+//
+// If abandon is true, hooks are abandoned once ctx is done.
+func callHooks(ctx context.Context, phase string, stageHooks []*hook, shutdownOptions *shutdownOptions, abandon bool) []HookReport {
+	hookReports := make([]HookReport, len(stageHooks))
+	callReport := func(i int) {
+		hookReports[i] = stageHooks[i].callReport(ctx, abandon)
+		hookReports[i].Phase = phase
+		if shutdownOptions.reportLogger != nil {
+			hookReports[i].log(shutdownOptions.reportLogger)
+		}
+	}
+	if !shutdownOptions.parallel {
+		for i := range stageHooks {
+			callReport(i)
+		}
+		return hookReports
+	}
+	var semaphoreC chan struct{}
+	if shutdownOptions.limit > 0 {
+		semaphoreC = make(chan struct{}, shutdownOptions.limit)
+	}
+	var wg sync.WaitGroup
+	for i := range stageHooks {
+		if semaphoreC != nil {
+			semaphoreC <- struct{}{}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			callReport(i)
+			if semaphoreC != nil {
+				<-semaphoreC
+			}
+		}()
+	}
+	wg.Wait()
+	return hookReports
+}
+
+// takeHooks unregisters all hooks, and returns them in the order in which they
+// are to be called, with the stages of hooks registered with [WithPhase]
+// resolved from sortedPhases.
+//
+// If keep is true, the hooks remain registered.
+func takeHooks(keep bool, sortedPhases []Phase) []*hook {
+	hooksLock.Lock()
+	takenHooks := slices.Clone(hooks)
+	if !keep {
+		hooks = nil
+	}
+	hooksLock.Unlock()
+	for i, takenHook := range takenHooks {
+		if takenHook.phase == "" {
+			continue
+		}
+		resolvedHook := *takenHook
+		resolvedHook.stage = StageDefault
+		for _, phase := range sortedPhases {
+			if phase.Name == takenHook.phase {
+				resolvedHook.stage = phase.Stage
+				break
+			}
+		}
+		takenHooks[i] = &resolvedHook
+	}
+	slices.Reverse(takenHooks)
+	slices.SortStableFunc(takenHooks, func(a *hook, b *hook) int {
+		return cmp.Compare(a.stage, b.stage)
+	})
+	return takenHooks
+}
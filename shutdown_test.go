@@ -0,0 +1,200 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recorder records the names of the hooks that were called, in order.
+type recorder struct {
+	lock  sync.Mutex
+	names []string
+}
+
+func (r *recorder) hook(name string) func(context.Context) error {
+	return func(context.Context) error {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		r.names = append(r.names, name)
+		return nil
+	}
+}
+
+func (r *recorder) called() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return slices.Clone(r.names)
+}
+
+func TestShutdownOrder(t *testing.T) {
+	resetGlobals(t)
+	var recorder recorder
+	OnShutdown(recorder.hook("close"), WithStage(StageClose))
+	OnShutdown(recorder.hook("first"))
+	OnShutdown(recorder.hook("second"))
+	OnShutdown(recorder.hook("stop accepting"), WithStage(StageStopAccepting))
+	OnShutdown(recorder.hook("drain"), WithStage(StageDrain+1))
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"stop accepting", "drain", "second", "first", "close"}
+	if called := recorder.called(); !slices.Equal(called, want) {
+		t.Errorf("hooks called in order %v, want %v", called, want)
+	}
+	// Hooks are called at most once.
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if called := recorder.called(); len(called) != len(want) {
+		t.Errorf("hooks called again: %v", called)
+	}
+	if state := ReadState(); state != StateStopped {
+		t.Errorf("ReadState() = %v, want %v", state, StateStopped)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	resetGlobals(t)
+	blockC := make(chan struct{})
+	defer close(blockC)
+	var recorder recorder
+	OnShutdown(func(context.Context) error {
+		<-blockC
+		return nil
+	}, WithTimeout(10*time.Millisecond), WithName("stuck"))
+	OnShutdown(recorder.hook("close"), WithStage(StageClose))
+	start := time.Now()
+	err := Shutdown(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > testTimeout {
+		t.Errorf("Shutdown() took %v", elapsed)
+	}
+	if called := recorder.called(); !slices.Equal(called, []string{"close"}) {
+		t.Errorf("hooks called after the abandoned hook: %v", called)
+	}
+}
+
+func TestShutdownParallel(t *testing.T) {
+	resetGlobals(t)
+	const limit = 2
+	var running, maxRunning atomic.Int32
+	var drainDone atomic.Int32
+	for range 4 {
+		OnShutdown(func(context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			drainDone.Add(1)
+			return nil
+		}, WithStage(StageDrain))
+	}
+	var closeSawDrained int32
+	OnShutdown(func(context.Context) error {
+		closeSawDrained = drainDone.Load()
+		return nil
+	}, WithStage(StageClose))
+	if err := Shutdown(context.Background(), WithParallel(limit)); err != nil {
+		t.Fatal(err)
+	}
+	if n := maxRunning.Load(); n != limit {
+		t.Errorf("%d hooks ran at once, want %d", n, limit)
+	}
+	if closeSawDrained != 4 {
+		t.Errorf("later stage started after %d of 4 hooks of the earlier stage", closeSawDrained)
+	}
+}
+
+func TestShutdownErrors(t *testing.T) {
+	resetGlobals(t)
+	err1 := errors.New("first")
+	err2 := errors.New("second")
+	var called atomic.Bool
+	OnShutdown(func(context.Context) error { return err1 })
+	OnShutdown(func(context.Context) error {
+		called.Store(true)
+		return nil
+	})
+	OnShutdown(func(context.Context) error { return err2 }, WithStage(StageClose))
+	err := Shutdown(context.Background())
+	if !errors.Is(err, err1) || !errors.Is(err, err2) {
+		t.Errorf("Shutdown() = %v, want both hook errors", err)
+	}
+	if !called.Load() {
+		t.Error("hook not called after an error")
+	}
+}
+
+func TestShutdownDryRun(t *testing.T) {
+	resetGlobals(t)
+	var recorder recorder
+	OnShutdown(recorder.hook("hook"))
+	for range 2 {
+		if err := Shutdown(context.Background(), WithDryRun()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if called := recorder.called(); !slices.Equal(called, []string{"hook", "hook"}) {
+		t.Errorf("hooks called %v, want [hook hook]", called)
+	}
+	if state := ReadState(); state != StateReady {
+		t.Errorf("ReadState() = %v after a dry run, want %v", state, StateReady)
+	}
+}
+
+func TestShutdownPhases(t *testing.T) {
+	resetGlobals(t)
+	blockC := make(chan struct{})
+	defer close(blockC)
+	DefinePhase(Phase{Name: "drain", Stage: StageDrain, Timeout: 10 * time.Millisecond})
+	DefinePhase(Phase{Name: "deregister", Stage: StageStopAccepting, Delay: 10 * time.Millisecond})
+	var recorder recorder
+	OnShutdown(func(context.Context) error {
+		<-blockC
+		return nil
+	}, WithPhase("drain"), WithName("stuck"))
+	OnShutdown(recorder.hook("drain"), WithPhase("drain"))
+	OnShutdown(recorder.hook("close"), WithStage(StageClose))
+	err := Shutdown(context.Background(), WithParallel(0))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if called := recorder.called(); !slices.Equal(called, []string{"drain", "close"}) {
+		t.Errorf("hooks called %v, want [drain close]", called)
+	}
+	report, _ := ReadShutdownReport()
+	for _, hookReport := range report.Hooks {
+		if hookReport.Stage == StageDrain && hookReport.Phase != "drain" {
+			t.Errorf("hook %q reported in phase %q, want drain", hookReport.Name, hookReport.Phase)
+		}
+	}
+	if report.Duration() < 10*time.Millisecond {
+		t.Errorf("Duration() = %v, want at least the delay of the deregister phase", report.Duration())
+	}
+}
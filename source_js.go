@@ -0,0 +1,79 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js
+
+package interrupt
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"syscall/js"
+)
+
+// startSource registers c with [signal.Notify] for sig, and returns a function
+// that stops the registration.
+//
+// The Go runtime does not deliver signals on js/wasm, so this maps the events
+// of the host instead. In a browser, c receives syscall.SIGTERM when the page
+// is unloaded, as opposed to being put into the back/forward cache. In Node.js,
+// c receives os.Interrupt and syscall.SIGTERM when the process receives SIGINT
+// and SIGTERM.
+func startSource(c chan os.Signal, sig os.Signal) (stop func()) {
+	signal.Notify(c, sig)
+	send := func() {
+		select {
+		case c <- sig:
+		default:
+		}
+	}
+	global := js.Global()
+	process := global.Get("process")
+	var release func()
+	switch {
+	case sig == syscall.SIGTERM && global.Get("addEventListener").Type() == js.TypeFunction:
+		f := js.FuncOf(func(_ js.Value, args []js.Value) any {
+			if len(args) == 0 || !args[0].Get("persisted").Truthy() {
+				send()
+			}
+			return nil
+		})
+		global.Call("addEventListener", "pagehide", f)
+		release = func() {
+			global.Call("removeEventListener", "pagehide", f)
+			f.Release()
+		}
+	case (sig == os.Interrupt || sig == syscall.SIGTERM) && process.Type() == js.TypeObject && process.Get("on").Type() == js.TypeFunction:
+		name := "SIGINT"
+		if sig == syscall.SIGTERM {
+			name = "SIGTERM"
+		}
+		f := js.FuncOf(func(js.Value, []js.Value) any {
+			send()
+			return nil
+		})
+		process.Call("on", name, f)
+		release = func() {
+			process.Call("removeListener", name, f)
+			f.Release()
+		}
+	}
+	return func() {
+		signal.Stop(c)
+		if release != nil {
+			release()
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package interrupt
+
+import (
+	"os"
+	"os/signal"
+)
+
+// startSource registers c with [signal.Notify] for sig, and returns a function
+// that stops the registration.
+func startSource(c chan os.Signal, sig os.Signal) (stop func()) {
+	signal.Notify(c, sig)
+	return func() {
+		signal.Stop(c)
+	}
+}
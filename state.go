@@ -0,0 +1,156 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+)
+
+var (
+	// stateChangeLock serializes transitions, so that the functions registered
+	// with OnStateChange are called in order, without holding stateLock.
+	stateChangeLock sync.Mutex
+	stateLock       sync.Mutex
+	state           = StateReady
+	// notifiedState is the last state that the functions registered with
+	// OnStateChange were called with. It is guarded by both locks, and only
+	// modified while holding stateChangeLock.
+	notifiedState = StateReady
+	// stateSubscriptions contains the functions registered with OnStateChange.
+	stateSubscriptions []*func(State)
+	// shutdownSignal is the first signal that started the shutdown of the
+	// program, if any.
+	shutdownSignal SignalEvent
+)
+
+// State is the shutdown state of the program.
+//
+// The state only moves forward, from [StateReady] to [StateDraining] to
+// [StateStopped], so that health checks, metrics, and middleware can all
+// consult one authoritative state without coordinating among themselves.
+type State int
+
+const (
+	// StateReady is the state before any interrupt signal is handled.
+	StateReady State = iota
+	// StateDraining is the state once an interrupt signal has been handled by a
+	// context returned by one of the Handle functions other than
+	// [HandleSignal], including during the delay of [WithPreStopDelay].
+	StateDraining
+	// StateStopped is the state once [Shutdown] has completed.
+	StateStopped
+)
+
+// String implements fmt.Stringer.
+func (s State) String() string {
+	switch s {
+	case StateReady:
+		return "ready"
+	case StateDraining:
+		return "draining"
+	case StateStopped:
+		return "stopped"
+	default:
+		return "State(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// ReadState returns the current shutdown [State] of the program.
+//
+// This is typically consulted by readiness checks:
+//
+//	if interrupt.ReadState() != interrupt.StateReady {
+//	  w.WriteHeader(http.StatusServiceUnavailable)
+//	  return
+//	}
+func ReadState() State {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	return state
+}
+
+// OnStateChange calls f with each [State] that the program moves to, until the
+// returned stop function is called.
+//
+// Calls to f are made sequentially, in the order of the transitions, from the
+// goroutine that caused the transition, so f should not block.
+func OnStateChange(f func(State)) (stop func()) {
+	subscription := &f
+	stateLock.Lock()
+	stateSubscriptions = append(stateSubscriptions, subscription)
+	stateLock.Unlock()
+	return sync.OnceFunc(func() {
+		stateLock.Lock()
+		defer stateLock.Unlock()
+		stateSubscriptions = slices.DeleteFunc(stateSubscriptions, func(other *func(State)) bool {
+			return other == subscription
+		})
+	})
+}
+
+// startShutdown records event as the signal that started the shutdown of the
+// program, unless one was already recorded, and moves the program to
+// StateDraining.
+//
+// The functions registered with OnStateChange are not called, so that the
+// caller can call notifyState once it no longer holds its own locks.
+func startShutdown(event SignalEvent) {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	if shutdownSignal.Signal == nil {
+		shutdownSignal = event
+	}
+	state = max(state, StateDraining)
+}
+
+// readShutdownSignal returns the signal that started the shutdown of the
+// program, if any.
+func readShutdownSignal() SignalEvent {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	return shutdownSignal
+}
+
+// advanceState moves the program to next, if it is not already in next or a
+// later state, and calls the functions registered with OnStateChange.
+func advanceState(next State) {
+	stateLock.Lock()
+	state = max(state, next)
+	stateLock.Unlock()
+	notifyState()
+}
+
+// notifyState calls the functions registered with OnStateChange with each state
+// that the program moved to since they were last called.
+func notifyState() {
+	stateChangeLock.Lock()
+	defer stateChangeLock.Unlock()
+	for {
+		stateLock.Lock()
+		if notifiedState >= state {
+			stateLock.Unlock()
+			return
+		}
+		notifiedState++
+		current := notifiedState
+		subscriptions := slices.Clone(stateSubscriptions)
+		stateLock.Unlock()
+		for _, subscription := range subscriptions {
+			(*subscription)(current)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStateTransitions(t *testing.T) {
+	resetGlobals(t)
+	var lock sync.Mutex
+	var states []State
+	stop := OnStateChange(func(state State) {
+		lock.Lock()
+		defer lock.Unlock()
+		states = append(states, state)
+	})
+	defer stop()
+	if state := ReadState(); state != StateReady {
+		t.Fatalf("ReadState() = %v, want %v", state, StateReady)
+	}
+	ctx := Handle(context.Background(), WithSignals(testInterrupt))
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	if state := ReadState(); state != StateDraining {
+		t.Errorf("ReadState() = %v after an interrupt, want %v", state, StateDraining)
+	}
+	// States only move forward.
+	otherCtx := Handle(context.Background(), WithSignals(testInterrupt))
+	Trigger(testInterrupt)
+	waitDone(t, otherCtx)
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if state := ReadState(); state != StateStopped {
+		t.Errorf("ReadState() = %v after Shutdown, want %v", state, StateStopped)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if want := []State{StateDraining, StateStopped}; !slices.Equal(states, want) {
+		t.Errorf("states %v, want %v", states, want)
+	}
+}
+
+func TestStateShutdownWithoutSignal(t *testing.T) {
+	resetGlobals(t)
+	var states []State
+	stop := OnStateChange(func(state State) {
+		states = append(states, state)
+	})
+	defer stop()
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Intermediate states are not skipped.
+	if want := []State{StateDraining, StateStopped}; !slices.Equal(states, want) {
+		t.Errorf("states %v, want %v", states, want)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	for state, want := range map[State]string{
+		StateReady:    "ready",
+		StateDraining: "draining",
+		StateStopped:  "stopped",
+		State(7):      "State(7)",
+	} {
+		if got := state.String(); got != want {
+			t.Errorf("State(%d).String() = %q, want %q", int(state), got, want)
+		}
+	}
+}
+
+func TestStateChangeStop(t *testing.T) {
+	resetGlobals(t)
+	var stop func()
+	calledC := make(chan State, 2)
+	stopStateChange := OnStateChange(func(state State) {
+		// Functions registered with OnStateChange may call back into the
+		// watcher that caused the transition.
+		stop()
+		calledC <- state
+	})
+	defer stopStateChange()
+	var ctx context.Context
+	ctx, stop = HandleWithStop(context.Background(), WithSignals(testInterrupt), WithPersistentHandling())
+	Trigger(testInterrupt)
+	waitDone(t, ctx)
+	select {
+	case state := <-calledC:
+		if state != StateDraining {
+			t.Errorf("called with %v, want %v", state, StateDraining)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("function registered with OnStateChange not called")
+	}
+	waitSubscriptions(t, 0)
+}
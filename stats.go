@@ -0,0 +1,76 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"maps"
+	"os"
+	"time"
+)
+
+// Stats are statistics about the signals received by this package.
+//
+// Signals are counted once when they arrive, regardless of how many contexts,
+// channels, and callbacks handle them. Signals delivered with [Trigger] are
+// included.
+type Stats struct {
+	// Counts contains the number of times that each signal was received.
+	Counts map[os.Signal]int
+	// Total is the total number of signals received.
+	Total int
+	// First is the first signal received, if any.
+	First SignalEvent
+	// Last is the most recent signal received, if any.
+	Last SignalEvent
+}
+
+// SignalEvent is the arrival of a signal.
+type SignalEvent struct {
+	// Signal is the signal that was received, or nil if none was received.
+	Signal os.Signal
+	// Time is the time that the signal was received.
+	Time time.Time
+}
+
+// ReadStats returns the statistics about the signals received by this package
+// during the lifetime of the program.
+//
+// This can be used by health endpoints and crash reports, for example to
+// report that SIGTERM was received 25 seconds ago:
+//
+//	stats := interrupt.ReadStats()
+//	if stats.Last.Signal != nil {
+//	  logger.Info("still draining", "signal", stats.Last.Signal, "since", time.Since(stats.Last.Time))
+//	}
+func ReadStats() Stats {
+	dispatchLock.Lock()
+	defer dispatchLock.Unlock()
+	readStats := stats
+	readStats.Counts = maps.Clone(stats.Counts)
+	return readStats
+}
+
+func (s *Stats) record(sig os.Signal, now time.Time) {
+	event := SignalEvent{
+		Signal: sig,
+		Time:   now,
+	}
+	if s.Total == 0 {
+		s.First = event
+	}
+	s.Last = event
+	s.Counts[sig]++
+	s.Total++
+}
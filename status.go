@@ -0,0 +1,43 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+)
+
+// OnStatus calls f whenever one of the [StatusSignals] arrives, until ctx is
+// done.
+//
+// On BSD-derived platforms such as macOS and FreeBSD, pressing Ctrl+T in a
+// terminal sends SIGINFO to the foreground process, and tools such as dd
+// respond by printing their progress. OnStatus allows programs to do the same,
+// without affecting interrupt handling:
+//
+//	interrupt.OnStatus(ctx, func() {
+//	  fmt.Fprintf(os.Stderr, "%d of %d files copied\n", copied.Load(), total)
+//	})
+//
+// Calls to f are made sequentially from a single goroutine. On platforms
+// without status signals, OnStatus has no effect.
+func OnStatus(ctx context.Context, f func()) {
+	if len(StatusSignals) == 0 {
+		return
+	}
+	onSignal(ctx, func(os.Signal) {
+		f()
+	}, StatusSignals)
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GracefulStopper is a server that can be stopped gracefully or forcibly.
+//
+// This is implemented by *grpc.Server, without this package depending on
+// gRPC.
+type GracefulStopper interface {
+	// GracefulStop stops accepting new connections and requests, and blocks
+	// until in-flight requests have completed.
+	GracefulStop()
+	// Stop closes all connections, and cancels in-flight requests.
+	Stop()
+}
+
+// GracefulStop blocks until ctx is done, and then stops server gracefully,
+// forcibly stopping it if the graceful stop does not complete within
+// gracePeriod.
+//
+// GracefulStop is typically run alongside the server:
+//
+//	ctx := interrupt.Handle(context.Background())
+//	server := grpc.NewServer()
+//	stopErrC := make(chan error, 1)
+//	go func() {
+//	  stopErrC <- interrupt.GracefulStop(ctx, server, 10*time.Second)
+//	}()
+//	if err := server.Serve(listener); err != nil {
+//	  return err
+//	}
+//	return <-stopErrC
+//
+// GracefulStop returns nil if the graceful stop completed, and an error
+// matching [context.DeadlineExceeded] if server had to be stopped forcibly. If
+// gracePeriod is not positive, the graceful stop is waited for indefinitely.
+func GracefulStop(ctx context.Context, server GracefulStopper, gracePeriod time.Duration) error {
+	<-ctx.Done()
+	stoppedC := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stoppedC)
+	}()
+	if gracePeriod <= 0 {
+		<-stoppedC
+		return nil
+	}
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+	select {
+	case <-stoppedC:
+		return nil
+	case <-timer.C:
+		server.Stop()
+		<-stoppedC
+		return fmt.Errorf("server did not stop gracefully within %v: %w", gracePeriod, context.DeadlineExceeded)
+	}
+}
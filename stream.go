@@ -0,0 +1,79 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Attach returns a copy of ctx that is also marked done when interruptCtx is
+// done, with the cause of interruptCtx, along with a function to release the
+// resources associated with it.
+//
+// This attaches the context returned by one of the Handle functions to the
+// context of a long-lived operation that was not derived from it, such as a
+// streaming RPC, whose context is derived from its connection. Without it,
+// server-streaming RPCs hold the drain open until the client disconnects.
+// Attach is the building block for stream interceptors, for example with
+// Connect:
+//
+//	func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+//	  return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+//	    ctx, cancel := interrupt.Attach(ctx, i.shutdownCtx)
+//	    defer cancel()
+//	    return next(ctx, conn)
+//	  }
+//	}
+//
+// Or with gRPC:
+//
+//	func streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+//	  ctx, cancel := interrupt.Attach(ss.Context(), shutdownCtx)
+//	  defer cancel()
+//	  return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+//	}
+func Attach(ctx context.Context, interruptCtx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stopAfterFunc := context.AfterFunc(interruptCtx, func() {
+		cancel(context.Cause(interruptCtx))
+	})
+	return ctx, func() {
+		stopAfterFunc()
+		cancel(context.Canceled)
+	}
+}
+
+// StreamMiddleware returns HTTP middleware that attaches ctx to the context of
+// streaming Connect RPCs with [Attach], so that they are canceled promptly once
+// ctx is done, while unary RPCs and other requests are left to complete.
+//
+// Streaming Connect RPCs are recognized by their "application/connect+"
+// content type. gRPC does not distinguish streaming RPCs at the HTTP level, so
+// gRPC streams should use Attach in a stream interceptor instead.
+func StreamMiddleware(ctx context.Context) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if !strings.HasPrefix(request.Header.Get("Content-Type"), "application/connect+") {
+				next.ServeHTTP(responseWriter, request)
+				return
+			}
+			requestCtx, cancel := Attach(request.Context(), ctx)
+			defer cancel()
+			next.ServeHTTP(responseWriter, request.WithContext(requestCtx))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// ErrReload is the cause of cancellation of the [context.Context] passed to
+// the function run by [Supervise] when a reload signal arrives, and of the
+// contexts of the generations of [Generations] that have ended.
+var ErrReload = errors.New("reload")
+
+// Supervise runs fn with a context that handles interrupt signals as with
+// [Handle], and re-runs fn whenever one of the [ReloadSignals] arrives.
+//
+// This is the classic daemon reload loop: when syscall.SIGHUP arrives, the
+// context passed to fn is canceled with [ErrReload] as the cause, Supervise
+// waits for fn to return, and then calls fn again with a new context, so that
+// fn can reread its configuration and reopen its resources. Interrupt signals
+// such as syscall.SIGTERM shut fn down for good:
+//
+//	err := interrupt.Supervise(ctx, func(ctx context.Context) error {
+//	  config, err := loadConfig()
+//	  if err != nil {
+//	    return err
+//	  }
+//	  return serve(ctx, config)
+//	})
+//
+// Supervise returns once fn returns other than because of a reload, with the
+// error as documented for [Run]. If fn returns an error other than one matching
+// [context.Canceled] when asked to reload, Supervise returns that error.
+// Supervise accepts the same [Option]s as [Handle], whose signals should not
+// include the reload signals.
+func Supervise(ctx context.Context, fn func(context.Context) error, opts ...Option) error {
+	options := newOptions(opts)
+	options.shutdown = true
+	ctx, stop := handle(ctx, options)
+	defer stop()
+	var reloadC <-chan os.Signal
+	if len(ReloadSignals) > 0 {
+		reloadC = Notify(ctx, ReloadSignals...)
+	}
+	for {
+		runCtx, cancel := context.WithCancelCause(ctx)
+		errC := make(chan error, 1)
+		go func() {
+			errC <- fn(runCtx)
+		}()
+		select {
+		case err := <-errC:
+			cancel(nil)
+			return interruptedError(ctx, err)
+		case sig, ok := <-reloadC:
+			if !ok {
+				// ctx is done, so wait for fn to return.
+				err := <-errC
+				cancel(nil)
+				return interruptedError(ctx, err)
+			}
+			options.log("reloading", sig)
+			cancel(ErrReload)
+			if err := <-errC; err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, ErrReload) {
+				return interruptedError(ctx, err)
+			}
+		}
+	}
+}
+
+// SuperviseCommand runs the command returned by command with [Supervise], so
+// that the command is restarted whenever one of the [ReloadSignals] arrives,
+// and stopped for good when an interrupt signal arrives.
+//
+// The context passed to command is canceled to stop the command, so command
+// should return a command that stops gracefully when it is done, such as one
+// created with [Command] or [GroupCommand]. When syscall.SIGHUP arrives, the
+// command is asked to terminate, and once it has exited, killed after its
+// WaitDelay if need be, command is called again to start a new one:
+//
+//	err := interrupt.SuperviseCommand(ctx, func(ctx context.Context) *exec.Cmd {
+//	  cmd := interrupt.Command(ctx, "worker", "-config", path)
+//	  cmd.Stdout = os.Stdout
+//	  cmd.Stderr = os.Stderr
+//	  return cmd
+//	})
+//
+// If the command exits on its own, SuperviseCommand returns the error returned
+// by its Run method, as documented for [Supervise], without restarting it.
+func SuperviseCommand(ctx context.Context, command func(context.Context) *exec.Cmd, opts ...Option) error {
+	return Supervise(ctx, func(ctx context.Context) error {
+		err := command(ctx).Run()
+		if ctx.Err() != nil {
+			// The command was stopped, so its exit status is of no interest.
+			return ctx.Err()
+		}
+		return err
+	}, opts...)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// setReloadSignals sets ReloadSignals to signals until t completes.
+func setReloadSignals(t *testing.T, signals ...os.Signal) {
+	t.Helper()
+	reloadSignals := ReloadSignals
+	ReloadSignals = signals
+	t.Cleanup(func() {
+		ReloadSignals = reloadSignals
+	})
+}
+
+func TestSupervise(t *testing.T) {
+	resetGlobals(t)
+	setReloadSignals(t, testReload)
+	runC := make(chan context.Context)
+	errC := make(chan error, 1)
+	go func() {
+		errC <- Supervise(context.Background(), func(ctx context.Context) error {
+			runC <- ctx
+			<-ctx.Done()
+			return ctx.Err()
+		}, WithSignals(testInterrupt))
+	}()
+	first := <-runC
+	// The reload signal is received by Notify once it is registered.
+	waitSubscriptions(t, 2)
+	Trigger(testReload)
+	second := <-runC
+	if cause := context.Cause(first); !errors.Is(cause, ErrReload) {
+		t.Errorf("Cause() = %v after a reload, want %v", cause, ErrReload)
+	}
+	assertNotDone(t, second)
+	Trigger(testInterrupt)
+	select {
+	case err := <-errC:
+		var signalErr *SignalError
+		if !errors.As(err, &signalErr) || signalErr.Signal != testInterrupt {
+			t.Errorf("Supervise() = %v, want the error for %v", err, testInterrupt)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("Supervise did not return after an interrupt")
+	}
+	waitSubscriptions(t, 0)
+}
+
+func TestSuperviseReloadError(t *testing.T) {
+	resetGlobals(t)
+	setReloadSignals(t, testReload)
+	reloadErr := errors.New("reload failed")
+	var runs int
+	errC := make(chan error, 1)
+	startedC := make(chan struct{})
+	go func() {
+		errC <- Supervise(context.Background(), func(ctx context.Context) error {
+			runs++
+			close(startedC)
+			<-ctx.Done()
+			return reloadErr
+		}, WithSignals(testInterrupt))
+	}()
+	<-startedC
+	waitSubscriptions(t, 2)
+	Trigger(testReload)
+	if err := <-errC; !errors.Is(err, reloadErr) {
+		t.Errorf("Supervise() = %v, want %v", err, reloadErr)
+	}
+	if runs != 1 {
+		t.Errorf("fn called %d times after a failed reload, want 1", runs)
+	}
+}
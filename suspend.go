@@ -0,0 +1,41 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import "context"
+
+// OnSuspend calls suspend before the program is suspended by the terminal, as
+// with Ctrl+Z, and resume once it continues, until ctx is done.
+//
+// Interactive programs that put the terminal into raw mode must restore it
+// before they stop, and re-enter raw mode when they continue, or the shell is
+// left unusable:
+//
+//	interrupt.OnSuspend(ctx, func() {
+//	  _ = term.Restore(fd, cookedState)
+//	}, func() {
+//	  _, _ = term.MakeRaw(fd)
+//	})
+//
+// When syscall.SIGTSTP arrives, OnSuspend calls suspend, and then stops the
+// program with syscall.SIGSTOP. When syscall.SIGCONT arrives, OnSuspend calls
+// resume. Calls to suspend and resume are made sequentially from a single
+// goroutine, and stop once ctx is done, so if ctx was returned by one of the
+// Handle functions, they do not race with the shutdown that follows an
+// interrupt. On platforms without job control, such as Windows, OnSuspend has
+// no effect.
+func OnSuspend(ctx context.Context, suspend func(), resume func()) {
+	onSuspend(ctx, suspend, resume)
+}
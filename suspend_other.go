@@ -16,10 +16,6 @@
 
 package interrupt
 
-import "os"
+import "context"
 
-// Signals are all interrupt signals.
-//
-// As opposed to os.Interrupt, this adds syscall.SIGTERM for unix-like platforms. For
-// other platforms, this is just os.Interrupt.
-var Signals = []os.Signal{os.Interrupt}
+func onSuspend(context.Context, func(), func()) {}
@@ -0,0 +1,37 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"syscall"
+)
+
+func onSuspend(ctx context.Context, suspend func(), resume func()) {
+	onSignal(ctx, func(sig os.Signal) {
+		switch sig {
+		case syscall.SIGTSTP:
+			suspend()
+			// SIGSTOP cannot be handled, so this stops the program as SIGTSTP
+			// would have if it were not handled.
+			_ = syscall.Kill(os.Getpid(), syscall.SIGSTOP)
+		case syscall.SIGCONT:
+			resume()
+		}
+	}, []os.Signal{syscall.SIGTSTP, syscall.SIGCONT})
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// systemdListenFDsStart is the first file descriptor passed by systemd, as
+// SD_LISTEN_FDS_START.
+const systemdListenFDsStart = 3
+
+// SystemdListeners returns the listeners passed by systemd socket activation,
+// in the order of the sockets of the unit, as [*Listener]s that stop accepting
+// connections once ctx is done.
+//
+// This allows services managed by systemd to use this package end to end,
+// without a separate activation library:
+//
+//	ctx := interrupt.Handle(context.Background())
+//	listeners, err := interrupt.SystemdListeners(ctx)
+//	if err != nil {
+//	  return err
+//	}
+//	return interrupt.ServeHTTP(ctx, server, listeners[0], 25*time.Second)
+//
+// If the program was not started by socket activation, as indicated by the
+// LISTEN_PID and LISTEN_FDS environment variables, SystemdListeners returns no
+// listeners. The environment variables are unset, so that they are not passed
+// on to child processes.
+func SystemdListeners(ctx context.Context) ([]*Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LISTEN_FDS: %w", err)
+	}
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+	listeners := make([]*Listener, 0, count)
+	for fd := systemdListenFDsStart; fd < systemdListenFDsStart+count; fd++ {
+		file := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			for _, listener := range listeners {
+				_ = listener.Close()
+			}
+			return nil, fmt.Errorf("socket activation file descriptor %d: %w", fd, err)
+		}
+		listeners = append(listeners, NewListener(ctx, listener))
+	}
+	return listeners, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"runtime/trace"
+	"time"
+)
+
+// defaultTraceDuration is the duration of the traces captured by
+// CaptureTrace if none is given.
+const defaultTraceDuration = 5 * time.Second
+
+// CaptureTrace captures an execution trace for duration, written to path,
+// whenever sig arrives, until ctx is done, for diagnosing issues with
+// scheduling or garbage collection with [go tool trace] on a live program:
+//
+//	interrupt.CaptureTrace(ctx, syscall.SIGUSR2, "/var/tmp/trace-*.out", 10*time.Second)
+//
+// The last "*" in path, if any, is replaced by the current time, as for
+// [DumpHeap]. A trace in progress is stopped and written when ctx is done, and
+// by [Shutdown] after the hooks of [StageClose], so that it is not lost when
+// the program exits. Signals that arrive while a trace is in progress are
+// ignored. A zero or negative duration defaults to 5 seconds.
+//
+// The capture is registered as an action with [OnAction], with the given
+// options, and the path of each trace written is logged. Only one trace can be
+// in progress in a program, so capturing a trace fails if another one is in
+// progress, as started with [trace.Start].
+//
+// [go tool trace]: https://pkg.go.dev/cmd/trace
+func CaptureTrace(ctx context.Context, sig os.Signal, path string, duration time.Duration, opts ...ActionOption) {
+	if duration <= 0 {
+		duration = defaultTraceDuration
+	}
+	actionOptions := newActionOptions(opts)
+	stop := onTimedAction(ctx, sig, "execution trace", duration, false, func(context.Context) (func(context.Context) error, error) {
+		return actionOptions.startFile("execution trace", path, trace.Start, trace.Stop)
+	}, opts)
+	OnShutdown(stop, WithStage(StageClose+1), WithName("execution trace"))
+}
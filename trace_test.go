@@ -0,0 +1,58 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCaptureTrace(t *testing.T) {
+	resetGlobals(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	pattern := filepath.Join(t.TempDir(), "trace-*.out")
+	CaptureTrace(ctx, testProfile, pattern, 200*time.Millisecond, WithActionLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+	Trigger(testProfile)
+	waitFiles(t, pattern)
+	// Signals that arrive while the trace is in progress are ignored.
+	Trigger(testProfile)
+	logs.wait(t, `msg="wrote execution trace" path=`)
+	paths := waitFiles(t, pattern)
+	if len(paths) != 1 {
+		t.Fatalf("traces = %v, want one", paths)
+	}
+	assertNotEmpty(t, paths)
+}
+
+func TestCaptureTraceCancel(t *testing.T) {
+	resetGlobals(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs logBuffer
+	pattern := filepath.Join(t.TempDir(), "trace-*.out")
+	CaptureTrace(ctx, testProfile, pattern, time.Hour, WithActionLogger(logs.logger()))
+	waitSubscriptions(t, 1)
+	Trigger(testProfile)
+	waitFiles(t, pattern)
+	// A trace in progress is written once ctx is done.
+	cancel()
+	logs.wait(t, `msg="wrote execution trace" path=`)
+	assertNotEmpty(t, waitFiles(t, pattern))
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import "os"
+
+// Trigger delivers sig to all signal handling registered by this package, as
+// if sig had arrived from the operating system.
+//
+// This trips the same path as a real signal, for every context, channel, and
+// callback that handles sig, which allows programmatic shutdowns, for example
+// from an admin RPC or a fatal internal condition, to be handled identically
+// to interrupts:
+//
+//	interrupt.Trigger(os.Interrupt)
+//
+// As with signals from the operating system, delivery does not block, and sig
+// is not delivered to channels that are not ready to receive it. Trigger has
+// no effect on signal handling that was not registered by this package, nor on
+// the default behavior of Go programs when no handling is registered.
+func Trigger(sig os.Signal) {
+	dispatch(sig)
+}
+
+// RequestShutdown delivers the signal that conventionally asks the program to
+// terminate gracefully with [Trigger], which is syscall.SIGTERM in unix-like
+// systems, Windows, and WebAssembly, and os.Interrupt on Plan 9.
+//
+// This bridges shutdown requests that do not arrive as signals into the same
+// path as interrupts. For example, a Windows service implemented with
+// golang.org/x/sys/windows/svc can run the same code as a console program,
+// without this package depending on it:
+//
+//	func (s *service) Execute(args []string, requests <-chan svc.ChangeRequest, statuses chan<- svc.Status) (bool, uint32) {
+//	  statuses <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+//	  for request := range requests {
+//	    switch request.Cmd {
+//	    case svc.Interrogate:
+//	      statuses <- request.CurrentStatus
+//	    case svc.Stop, svc.Shutdown:
+//	      statuses <- svc.Status{State: svc.StopPending}
+//	      interrupt.RequestShutdown()
+//	      <-s.done
+//	      return false, 0
+//	    }
+//	  }
+//	  return false, 0
+//	}
+func RequestShutdown() {
+	Trigger(terminateSignal)
+}
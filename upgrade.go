@@ -0,0 +1,350 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// upgradeListenersEnv contains the names of the listeners passed to a new
+	// process by Upgrade, separated by upgradeListenersSep, in the order of
+	// their file descriptors, starting at 3.
+	upgradeListenersEnv = "INTERRUPT_UPGRADE_LISTENERS"
+	// upgradeReadyEnv contains the file descriptor that a new process started
+	// by Upgrade writes to once it is ready.
+	upgradeReadyEnv = "INTERRUPT_UPGRADE_READY_FD"
+	// upgradeListenersSep separates the names in upgradeListenersEnv.
+	upgradeListenersSep = "\x1f"
+)
+
+// ErrUpgraded is the error returned by [Upgrader.Upgrade] once a new process
+// has taken over.
+var ErrUpgraded = errors.New("process already upgraded")
+
+// Upgrader restarts the program without downtime, by passing its listening
+// sockets to a new process started from the same executable, and draining once
+// the new process is ready, in the manner of tableflip.
+//
+// An upgrade is started by one of the [UpgradeSignals] arriving, or by calling
+// [Upgrader.Upgrade]. Signals are received through this package, so that the
+// upgrade and interrupt handling share one owner of signal handling. Once the
+// new process has called [Upgrader.Ready], the channel returned by
+// [Upgrader.Done] is closed, which is typically used with [WithDone] to drain
+// the old process as if it were interrupted:
+//
+//	upgrader, err := interrupt.NewUpgrader(context.Background(), 30*time.Second)
+//	if err != nil {
+//	  return err
+//	}
+//	listener, err := upgrader.Listen("tcp", ":8080")
+//	if err != nil {
+//	  return err
+//	}
+//	ctx := interrupt.Handle(context.Background(), interrupt.WithDone(upgrader.Done()))
+//	go interrupt.ServeHTTP(ctx, server, listener, 25*time.Second)
+//	if err := upgrader.Ready(); err != nil {
+//	  return err
+//	}
+//	<-ctx.Done()
+//
+// Upgrades are supported on unix-like platforms only.
+type Upgrader struct {
+	readyTimeout time.Duration
+	logger       *slog.Logger
+	// inherited contains the listeners passed by the parent process, by name,
+	// that have not been claimed with Listen yet.
+	inherited map[string]net.Listener
+	// readyFile is the file to notify the parent process with, if any.
+	readyFile *os.File
+	doneC     chan struct{}
+
+	lock      sync.Mutex
+	listeners []upgradeListener
+	upgrading bool
+	upgraded  bool
+}
+
+type upgradeListener struct {
+	name     string
+	listener net.Listener
+}
+
+// NewUpgrader returns a new [Upgrader], which inherits the listeners of the
+// parent process if the program was started by an upgrade.
+//
+// Until ctx is done, an upgrade is started whenever one of the
+// [UpgradeSignals] arrives, and upgrades that fail are logged, as configured
+// with [WithUpgradeLogger]. An upgrade fails if the new process has not called
+// [Upgrader.Ready] within readyTimeout, in which case the new process is
+// killed. If readyTimeout is not positive, the new process is waited for
+// indefinitely.
+func NewUpgrader(ctx context.Context, readyTimeout time.Duration, opts ...UpgradeOption) (*Upgrader, error) {
+	u := &Upgrader{
+		readyTimeout: readyTimeout,
+		logger:       slog.Default(),
+		inherited:    make(map[string]net.Listener),
+		doneC:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	if err := u.inherit(); err != nil {
+		return nil, err
+	}
+	if len(UpgradeSignals) > 0 {
+		onSignal(ctx, func(sig os.Signal) {
+			attrs := []slog.Attr{slog.String("signal", sig.String())}
+			u.logger.LogAttrs(ctx, slog.LevelInfo, "upgrading", attrs...)
+			if err := u.Upgrade(); err != nil && !errors.Is(err, ErrUpgraded) {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				u.logger.LogAttrs(ctx, slog.LevelError, "upgrade failed", attrs...)
+			}
+		}, UpgradeSignals)
+	}
+	return u, nil
+}
+
+// UpgradeOption is an option for [NewUpgrader].
+type UpgradeOption func(*Upgrader)
+
+// WithUpgradeLogger returns a new UpgradeOption that logs the upgrades started
+// by the [UpgradeSignals], and the errors of failed upgrades, to the given
+// [*slog.Logger].
+//
+// The default is to log to [slog.Default].
+func WithUpgradeLogger(logger *slog.Logger) UpgradeOption {
+	return func(u *Upgrader) {
+		u.logger = logger
+	}
+}
+
+// Listen returns the listener for the given network and address inherited
+// from the parent process, if any, and otherwise listens with [net.Listen].
+//
+// Listeners are matched by network and address as given, so the new process
+// must call Listen with the same arguments as the old one. The listener is
+// passed to the new process by subsequent upgrades.
+func (u *Upgrader) Listen(network string, address string) (net.Listener, error) {
+	name := network + ":" + address
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	listener, ok := u.inherited[name]
+	if ok {
+		delete(u.inherited, name)
+	} else {
+		var err error
+		listener, err = net.Listen(network, address)
+		if err != nil {
+			return nil, err
+		}
+	}
+	u.listeners = append(u.listeners, upgradeListener{name: name, listener: listener})
+	return listener, nil
+}
+
+// Ready notifies the parent process, if the program was started by an
+// upgrade, that the program is ready, so that the parent process drains.
+//
+// Ready closes the inherited listeners that were not claimed with
+// [Upgrader.Listen]. It should be called once all listeners have been
+// obtained and the program is serving.
+func (u *Upgrader) Ready() error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	var errs []error
+	for name, listener := range u.inherited {
+		errs = append(errs, listener.Close())
+		delete(u.inherited, name)
+	}
+	if u.readyFile != nil {
+		_, err := u.readyFile.Write([]byte{1})
+		errs = append(errs, err, u.readyFile.Close())
+		u.readyFile = nil
+	}
+	return errors.Join(errs...)
+}
+
+// Done returns a channel that is closed once a new process started by an
+// upgrade is ready, at which point the program should drain and exit.
+func (u *Upgrader) Done() <-chan struct{} {
+	return u.doneC
+}
+
+// Upgrade starts a new process from the executable of the program, with the
+// same arguments, passing it the listeners obtained with [Upgrader.Listen], and
+// waits for it to call [Upgrader.Ready].
+//
+// If the new process is ready, the channel returned by [Upgrader.Done] is
+// closed, and subsequent calls return [ErrUpgraded]. Otherwise, an error is
+// returned, and the program continues as if no upgrade had been attempted.
+func (u *Upgrader) Upgrade() error {
+	u.lock.Lock()
+	if u.upgraded {
+		u.lock.Unlock()
+		return ErrUpgraded
+	}
+	if u.upgrading {
+		u.lock.Unlock()
+		return errors.New("upgrade already in progress")
+	}
+	u.upgrading = true
+	listeners := u.listeners
+	u.lock.Unlock()
+	err := upgrade(listeners, u.readyTimeout)
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.upgrading = false
+	if err != nil {
+		return err
+	}
+	u.upgraded = true
+	close(u.doneC)
+	return nil
+}
+
+// inherit obtains the listeners and the ready file passed by the parent
+// process, if any.
+func (u *Upgrader) inherit() error {
+	names, ok := os.LookupEnv(upgradeListenersEnv)
+	if !ok {
+		return nil
+	}
+	readyFD, err := strconv.Atoi(os.Getenv(upgradeReadyEnv))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", upgradeReadyEnv, err)
+	}
+	// Do not pass the environment on to unrelated child processes.
+	_ = os.Unsetenv(upgradeListenersEnv)
+	_ = os.Unsetenv(upgradeReadyEnv)
+	u.readyFile = os.NewFile(uintptr(readyFD), "ready")
+	if names == "" {
+		return nil
+	}
+	splitNames := strings.Split(names, upgradeListenersSep)
+	for i, name := range splitNames {
+		file := os.NewFile(uintptr(3+i), name)
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			// Close everything passed by the parent process, since the
+			// Upgrader is not returned.
+			for name, listener := range u.inherited {
+				_ = listener.Close()
+				delete(u.inherited, name)
+			}
+			for j := i + 1; j < len(splitNames); j++ {
+				_ = os.NewFile(uintptr(3+j), splitNames[j]).Close()
+			}
+			_ = u.readyFile.Close()
+			u.readyFile = nil
+			return fmt.Errorf("inherit listener %s: %w", name, err)
+		}
+		u.inherited[name] = listener
+	}
+	return nil
+}
+
+// upgrade starts a new process with the given listeners, and waits for it to
+// become ready.
+func upgrade(listeners []upgradeListener, readyTimeout time.Duration) error {
+	if !canPassFiles {
+		return fmt.Errorf("upgrade: %w", errors.ErrUnsupported)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(listeners))
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+	for i, listener := range listeners {
+		filer, ok := listener.listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("listener %s cannot be passed to a new process", listener.name)
+		}
+		file, err := filer.File()
+		if err != nil {
+			return err
+		}
+		names[i] = listener.name
+		files = append(files, file)
+	}
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyReader.Close()
+	files = append(files, readyWriter)
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(
+		os.Environ(),
+		upgradeListenersEnv+"="+strings.Join(names, upgradeListenersSep),
+		upgradeReadyEnv+"="+strconv.Itoa(3+len(names)),
+	)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Close our copy of the write end, so that reading fails if the new process
+	// exits without becoming ready.
+	_ = readyWriter.Close()
+	files = files[:len(files)-1]
+	readyC := make(chan error, 1)
+	go func() {
+		_, err := readyReader.Read(make([]byte, 1))
+		if errors.Is(err, io.EOF) {
+			err = errors.New("new process exited before becoming ready")
+		}
+		readyC <- err
+	}()
+	var timeoutC <-chan time.Time
+	if readyTimeout > 0 {
+		timer := time.NewTimer(readyTimeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+	select {
+	case err = <-readyC:
+	case <-timeoutC:
+		err = fmt.Errorf("new process not ready within %v", readyTimeout)
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+	// The new process outlives this one, so it is not waited for.
+	_ = cmd.Process.Release()
+	return nil
+}
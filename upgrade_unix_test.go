@@ -0,0 +1,222 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const (
+	// testUpgradeEnv selects the behavior of the test binary when it is started
+	// as a new process by the tests of Upgrader.
+	testUpgradeEnv = "INTERRUPT_TEST_UPGRADE"
+	// testUpgradeAddress is the address listened on by the tests of Upgrader.
+	testUpgradeAddress = "127.0.0.1:0"
+)
+
+func init() {
+	var err error
+	switch os.Getenv(testUpgradeEnv) {
+	case "":
+		return
+	case "exit":
+		// Exit without becoming ready.
+	case "ready":
+		err = runUpgraded()
+	case "inherit":
+		err = runInheritFailure()
+	}
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// runUpgraded runs the new process of TestUpgrader, which serves a single
+// connection on the listener passed by the old process.
+func runUpgraded() error {
+	upgrader, err := NewUpgrader(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+	listener, err := upgrader.Listen("tcp", testUpgradeAddress)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	if err := upgrader.Ready(); err != nil {
+		return err
+	}
+	// Do not outlive a failed test.
+	if err := listener.(*net.TCPListener).SetDeadline(time.Now().Add(testTimeout)); err != nil {
+		return err
+	}
+	conn, err := listener.Accept()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = io.WriteString(conn, "upgraded")
+	return err
+}
+
+// runInheritFailure runs the process of TestUpgraderInheritFailure, which
+// checks that the files passed by the parent process are closed once
+// inheriting fails.
+func runInheritFailure() error {
+	if _, err := NewUpgrader(context.Background(), 0); err == nil {
+		return errors.New("NewUpgrader() = nil with an invalid listener")
+	}
+	for fd := 3; fd <= 5; fd++ {
+		var stat syscall.Stat_t
+		if err := syscall.Fstat(fd, &stat); !errors.Is(err, syscall.EBADF) {
+			return fmt.Errorf("file descriptor %d still open after NewUpgrader failed", fd)
+		}
+	}
+	return nil
+}
+
+func TestUpgrader(t *testing.T) {
+	resetGlobals(t)
+	t.Setenv(testUpgradeEnv, "ready")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	upgrader, err := NewUpgrader(ctx, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := upgrader.Listen("tcp", testUpgradeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	if err := upgrader.Ready(); err != nil {
+		t.Fatal(err)
+	}
+	if err := upgrader.Upgrade(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-upgrader.Done():
+	default:
+		t.Error("Done() not closed once the new process is ready")
+	}
+	if err := upgrader.Upgrade(); !errors.Is(err, ErrUpgraded) {
+		t.Errorf("Upgrade() = %v after an upgrade, want %v", err, ErrUpgraded)
+	}
+	// Once the old process stops accepting, connections are accepted by the new
+	// process, on the same socket.
+	address := listener.Addr().String()
+	if err := listener.Close(); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(testTimeout)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "upgraded" {
+		t.Errorf("read %q from the new process, want %q", data, "upgraded")
+	}
+}
+
+func TestUpgraderNotReady(t *testing.T) {
+	resetGlobals(t)
+	t.Setenv(testUpgradeEnv, "exit")
+	upgradeSignals := UpgradeSignals
+	UpgradeSignals = []os.Signal{testReload}
+	defer func() {
+		UpgradeSignals = upgradeSignals
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logBuffer logBuffer
+	upgrader, err := NewUpgrader(ctx, testTimeout, WithUpgradeLogger(logBuffer.logger()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := upgrader.Listen("tcp", testUpgradeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	if err := upgrader.Upgrade(); err == nil {
+		t.Fatal("Upgrade() = nil with a new process that is not ready")
+	}
+	// Upgrades started by signals report their errors to the logger.
+	Trigger(testReload)
+	logBuffer.wait(t, "upgrade failed")
+	select {
+	case <-upgrader.Done():
+		t.Error("Done() closed after a failed upgrade")
+	default:
+	}
+}
+
+func TestUpgraderInheritFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", testUpgradeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	listenerFile, err := listener.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listenerFile.Close()
+	// A regular file is not a listener.
+	regularFile, err := os.CreateTemp(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer regularFile.Close()
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer readyReader.Close()
+	defer readyWriter.Close()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(
+		os.Environ(),
+		testUpgradeEnv+"=inherit",
+		upgradeListenersEnv+"="+strings.Join([]string{"tcp:first", "tcp:second"}, upgradeListenersSep),
+		upgradeReadyEnv+"=5",
+	)
+	cmd.ExtraFiles = []*os.File{listenerFile, regularFile, readyWriter}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("%v: %s", err, output)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// watcher implements the signal handling for a context returned by handle.
+//
+// A watcher does not use a goroutine of its own. It is driven by the signals
+// delivered by dispatch, and by [context.AfterFunc] and [time.AfterFunc]
+// callbacks, so that programs that handle many short-lived contexts do not
+// accumulate goroutines.
+type watcher struct {
+	options *options
+	parent  context.Context
+	cancel  context.CancelCauseFunc
+
+	lock         sync.Mutex
+	subscription *subscription
+	// subscribed is true until signal handling is unregistered.
+	subscribed bool
+	// stopped is true once the stop function returned by handle is called.
+	stopped bool
+	// first is the first signal received, if any.
+	first os.Signal
+	// count is the number of signals received, excluding debounced signals.
+	count int
+	// debounceTimer is non-nil while signals are being debounced.
+	debounceTimer    *time.Timer
+	gracePeriodTimer *time.Timer
+	preStopTimer     *time.Timer
+	// stopParentAfterFunc stops watching the parent once a signal was
+	// received, if signal handling remains registered.
+	stopParentAfterFunc func() bool
+}
+
+func handle(parent context.Context, options *options) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	w := &watcher{
+		options:    options,
+		parent:     parent,
+		cancel:     cancel,
+		subscribed: true,
+	}
+	w.lock.Lock()
+	w.subscription = subscribe(w.receive, options.signals...)
+	w.lock.Unlock()
+	context.AfterFunc(ctx, w.done)
+	if options.brokenPipe && len(brokenPipeSignals) > 0 {
+		onSignal(ctx, func(os.Signal) {
+			cancel(ErrBrokenPipe)
+		}, brokenPipeSignals)
+	}
+	// There is no equivalent of context.AfterFunc for channels, so each done
+	// channel requires a goroutine.
+	for _, done := range options.done {
+		go func() {
+			select {
+			case <-done:
+				cancel(nil)
+			case <-ctx.Done():
+			}
+		}()
+	}
+	value := handledValue{
+		ctx:    ctx,
+		parent: parent,
+	}
+	return context.WithValue(ctx, handledContextKey{}, value), sync.OnceFunc(w.stop)
+}
+
+// handledContextKey is the key for the handledValue of a context returned by
+// handle.
+type handledContextKey struct{}
+
+type handledValue struct {
+	// ctx is the context returned by handle, before the key is added, which is
+	// marked done by signals.
+	ctx context.Context
+	// parent is the parent passed to handle.
+	parent context.Context
+}
+
+// handledContext returns the nearest context returned by handle that ctx is
+// derived from, or ctx if none.
+func handledContext(ctx context.Context) context.Context {
+	if value, ok := ctx.Value(handledContextKey{}).(handledValue); ok {
+		return value.ctx
+	}
+	return ctx
+}
+
+// handledParent returns the parent of the nearest context returned by handle
+// that ctx is derived from, if any.
+func handledParent(ctx context.Context) (context.Context, bool) {
+	value, ok := ctx.Value(handledContextKey{}).(handledValue)
+	return value.parent, ok
+}
+
+// receive is called by dispatch with each signal that arrives.
+func (w *watcher) receive(sig os.Signal) {
+	w.record(sig)
+	// The functions registered with OnStateChange are called without holding
+	// the lock, since they may call functions that take it, such as the stop
+	// function returned by handle.
+	notifyState()
+}
+
+// record records sig while holding the lock.
+func (w *watcher) record(sig os.Signal) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !w.subscribed {
+		return
+	}
+	if w.debounceTimer != nil {
+		w.options.log("debounced signal", sig)
+		return
+	}
+	w.count++
+	switch {
+	case w.options.debounce > 0:
+		w.debounceTimer = time.AfterFunc(w.options.debounce, w.debounced)
+	case !w.options.keepHandling(w.count):
+		// Unregister before canceling, so that a signal that arrives as soon as
+		// the context is done results in the default behavior.
+		w.unsubscribeLocked()
+	}
+	w.options.log("received signal", sig)
+	if w.count == 1 {
+		w.first = sig
+		cause := &SignalError{Signal: sig, Time: time.Now()}
+		if w.options.shutdown {
+			startShutdown(SignalEvent{Signal: sig, Time: cause.Time})
+		}
+		if w.options.preStopDelay > 0 {
+			w.preStopTimer = time.AfterFunc(w.options.preStopDelay, func() {
+				w.options.log("pre-stop delay elapsed", sig)
+				w.cancel(cause)
+			})
+		} else {
+			w.cancel(cause)
+		}
+		if w.subscribed {
+			w.stopParentAfterFunc = context.AfterFunc(w.parent, w.parentDone)
+		}
+		if w.options.gracePeriod > 0 {
+			w.gracePeriodTimer = time.AfterFunc(w.options.gracePeriod, w.gracePeriodElapsed)
+		}
+	}
+	w.options.signaled(sig, w.count)
+	if w.count > 1 && w.options.hasForceExit() && w.count >= w.options.signalThreshold {
+		w.options.forceExit(sig)
+	}
+}
+
+// done is called when the context returned by handle is done.
+func (w *watcher) done() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.count == 0 {
+		w.unsubscribeLocked()
+	}
+}
+
+// parentDone is called when the parent is done after a signal was received.
+func (w *watcher) parentDone() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.unsubscribeLocked()
+}
+
+func (w *watcher) debounced() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.debounceTimer = nil
+	if !w.options.keepHandling(w.count) {
+		w.unsubscribeLocked()
+	}
+}
+
+func (w *watcher) gracePeriodElapsed() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.stopped {
+		return
+	}
+	w.options.log("grace period elapsed", w.first)
+	w.options.forceExit(w.first)
+}
+
+func (w *watcher) stop() {
+	w.cancel(nil)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.stopped = true
+	w.unsubscribeLocked()
+	if w.gracePeriodTimer != nil {
+		w.gracePeriodTimer.Stop()
+	}
+	if w.preStopTimer != nil {
+		w.preStopTimer.Stop()
+	}
+}
+
+func (w *watcher) unsubscribeLocked() {
+	if !w.subscribed {
+		return
+	}
+	w.subscribed = false
+	unsubscribe(w.subscription)
+	if w.debounceTimer != nil {
+		w.debounceTimer.Stop()
+		w.debounceTimer = nil
+	}
+	if w.stopParentAfterFunc != nil {
+		w.stopParentAfterFunc()
+	}
+}